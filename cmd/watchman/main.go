@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/adrianpk/watchman/internal/cli"
 	"github.com/adrianpk/watchman/internal/config"
@@ -28,10 +32,117 @@ func main() {
 func runCommand(cmd string) error {
 	switch cmd {
 	case "init":
-		local := len(os.Args) > 2 && os.Args[2] == "--local"
-		return cli.RunInit(local)
+		var local bool
+		var preset string
+		args := os.Args[2:]
+		for i := 0; i < len(args); i++ {
+			switch {
+			case args[i] == "--local":
+				local = true
+			case args[i] == "--preset":
+				if i+1 >= len(args) {
+					return fmt.Errorf("usage: watchman init [--local] [--preset go|node|python]")
+				}
+				i++
+				preset = args[i]
+			case strings.HasPrefix(args[i], "--preset="):
+				preset = strings.TrimPrefix(args[i], "--preset=")
+			default:
+				return fmt.Errorf("unknown init flag: %s", args[i])
+			}
+		}
+		return cli.RunInit(local, preset)
 	case "setup":
 		return cli.RunSetup()
+	case "check":
+		format := "json"
+		var strict bool
+		args := os.Args[2:]
+		for i := 0; i < len(args); i++ {
+			switch {
+			case args[i] == "--json":
+				format = "json"
+			case args[i] == "--strict-exit":
+				strict = true
+			case args[i] == "--format":
+				if i+1 >= len(args) {
+					return fmt.Errorf("usage: watchman check [--format text|json|yaml] [--strict-exit]")
+				}
+				i++
+				format = args[i]
+			case strings.HasPrefix(args[i], "--format="):
+				format = strings.TrimPrefix(args[i], "--format=")
+			default:
+				return fmt.Errorf("unknown check flag: %s", args[i])
+			}
+		}
+		return cli.RunCheck(format, strict)
+	case "config":
+		if len(os.Args) < 3 {
+			return fmt.Errorf("usage: watchman config --effective | config migrate")
+		}
+		switch os.Args[2] {
+		case "--effective":
+			return cli.RunConfigEffective()
+		case "migrate":
+			return cli.RunConfigMigrate()
+		default:
+			return fmt.Errorf("usage: watchman config --effective | config migrate")
+		}
+	case "explain":
+		var command, format string
+		args := os.Args[2:]
+		for i := 0; i < len(args); i++ {
+			switch {
+			case args[i] == "--command":
+				if i+1 >= len(args) {
+					return fmt.Errorf("usage: watchman explain --command \"<command>\" [--format text|json|yaml]")
+				}
+				i++
+				command = args[i]
+			case args[i] == "--format":
+				if i+1 >= len(args) {
+					return fmt.Errorf("usage: watchman explain --command \"<command>\" [--format text|json|yaml]")
+				}
+				i++
+				format = args[i]
+			case strings.HasPrefix(args[i], "--format="):
+				format = strings.TrimPrefix(args[i], "--format=")
+			default:
+				return fmt.Errorf("unknown explain flag: %s", args[i])
+			}
+		}
+		if command == "" {
+			return fmt.Errorf("usage: watchman explain --command \"<command>\" [--format text|json|yaml]")
+		}
+		return cli.RunExplain(command, format)
+	case "stats":
+		return cli.RunStats()
+	case "audit":
+		if len(os.Args) < 4 || os.Args[2] != "replay" {
+			return fmt.Errorf("usage: watchman audit replay <logfile>")
+		}
+		return cli.RunAuditReplay(os.Args[3])
+	case "test-fixtures":
+		if len(os.Args) < 3 {
+			return fmt.Errorf("usage: watchman test-fixtures <dir>")
+		}
+		return cli.RunTestFixtures(os.Args[2])
+	case "scope":
+		if len(os.Args) < 3 {
+			return fmt.Errorf("usage: watchman scope add <pattern> | scope clear")
+		}
+		switch os.Args[2] {
+		case "add":
+			if len(os.Args) < 4 {
+				return fmt.Errorf("usage: watchman scope add <pattern>")
+			}
+			return cli.RunScopeAdd(os.Args[3])
+		case "clear":
+			return cli.RunScopeClear()
+		default:
+			return fmt.Errorf("unknown scope subcommand: %s", os.Args[2])
+		}
 	default:
 		return fmt.Errorf("unknown command: %s", cmd)
 	}
@@ -46,8 +157,24 @@ func runHook() error {
 
 	evaluator := hook.NewEvaluator(cfg)
 
+	var stdin io.Reader = os.Stdin
+	if cfg.MaxInputBytes > 0 {
+		// Read one byte past the limit so oversized input is detected
+		// without decoding it.
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, int64(cfg.MaxInputBytes)+1))
+		if err != nil {
+			deny("watchman input error: " + err.Error())
+			return nil
+		}
+		if len(data) > cfg.MaxInputBytes {
+			deny("watchman input exceeds max_input_bytes (" + strconv.Itoa(cfg.MaxInputBytes) + " bytes)")
+			return nil
+		}
+		stdin = bytes.NewReader(data)
+	}
+
 	var input hookInput
-	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil {
+	if err := json.NewDecoder(stdin).Decode(&input); err != nil {
 		deny("watchman input error: " + err.Error())
 		return nil
 	}
@@ -59,11 +186,15 @@ func runHook() error {
 	})
 
 	if !result.Allowed {
+		if result.Ask {
+			ask(result.Reason)
+			return nil
+		}
 		deny(result.Reason)
 		return nil
 	}
 
-	allow(result.Warning)
+	allow(result.Warning())
 	return nil
 }
 
@@ -93,6 +224,32 @@ func allow(additionalContext string) {
 		},
 	}
 	json.NewEncoder(os.Stdout).Encode(out)
+	warn(additionalContext)
+	os.Exit(0)
+}
+
+// warn prints each aggregated warning on its own stderr line with a final
+// count, so multiple warnings are not missed in the joined form.
+func warn(joined string) {
+	if joined == "" {
+		return
+	}
+	warnings := strings.Split(joined, "; ")
+	for _, w := range warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+w)
+	}
+	fmt.Fprintf(os.Stderr, "%d warning(s)\n", len(warnings))
+}
+
+func ask(reason string) {
+	out := hookOutput{
+		HookSpecificOutput: &hookSpecificOutput{
+			HookEventName:      "PreToolUse",
+			PermissionDecision: "ask",
+			Reason:             reason,
+		},
+	}
+	json.NewEncoder(os.Stdout).Encode(out)
 	os.Exit(0)
 }
 
@@ -3,9 +3,11 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -271,3 +273,66 @@ func TestWatchmanInvalidJSON(t *testing.T) {
 		t.Error("expected error message for invalid JSON")
 	}
 }
+
+func TestWatchmanWarningLines(t *testing.T) {
+	// Enabled-but-empty scope and versioning each emit a config warning
+	tmpDir := t.TempDir()
+	cfg := "version: 1\nrules:\n  workspace: true\n  scope: true\n  versioning: true\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".watchman.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(binaryPath)
+	cmd.Dir = tmpDir
+	cmd.Stdin = bytes.NewBufferString(makeInput("ls ."))
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("expected exit 0, got error: %v (stderr: %s)", err, errBuf.String())
+	}
+
+	stderr := errBuf.String()
+	if got := strings.Count(stderr, "warning: "); got != 2 {
+		t.Errorf("expected 2 warning lines, got %d:\n%s", got, stderr)
+	}
+	if !strings.Contains(stderr, "2 warning(s)") {
+		t.Errorf("expected final warning count, got:\n%s", stderr)
+	}
+}
+
+func TestWatchmanMaxInputBytes(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := "version: 1\nmax_input_bytes: 256\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".watchman.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Oversized input is denied with the size in the reason
+	big := makeInput("echo " + strings.Repeat("x", 1024))
+	cmd := exec.Command(binaryPath)
+	cmd.Dir = tmpDir
+	cmd.Stdin = bytes.NewBufferString(big)
+
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("expected exit 2 for oversized input, got %v", err)
+	}
+	if !strings.Contains(errBuf.String(), "max_input_bytes (256 bytes)") {
+		t.Errorf("stderr = %q, want size reason", errBuf.String())
+	}
+
+	// Input under the limit is processed normally
+	cmd = exec.Command(binaryPath)
+	cmd.Dir = tmpDir
+	cmd.Stdin = bytes.NewBufferString(makeInput("ls ."))
+	if err := cmd.Run(); err != nil {
+		t.Errorf("expected exit 0 for small input, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/hook"
+)
+
+// auditEntry is one line of the structured audit log: the full hook input
+// plus the decision watchman made at the time.
+type auditEntry struct {
+	HookType  string                 `json:"hook_type,omitempty"`
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input"`
+	Decision  string                 `json:"decision"` // "allow", "deny" or "ask"
+	Code      string                 `json:"code,omitempty"`
+	Reason    string                 `json:"reason,omitempty"`
+}
+
+// RunAuditReplay re-evaluates each logged input against the current config
+// and reports which decisions flipped, so config changes can be validated
+// against real traffic before they bite.
+func RunAuditReplay(logPath string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("cannot load config: %w", err)
+	}
+
+	f, err := os.Open(logPath)
+	if err != nil {
+		return fmt.Errorf("cannot open audit log: %w", err)
+	}
+	defer f.Close()
+
+	return runAuditReplay(cfg, f, os.Stdout)
+}
+
+// runAuditReplay is the testable core of RunAuditReplay.
+func runAuditReplay(cfg *config.Config, in io.Reader, out io.Writer) error {
+	evaluator := hook.NewEvaluator(cfg)
+	scanner := bufio.NewScanner(in)
+
+	line, flipped, unchanged := 0, 0, 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var entry auditEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return fmt.Errorf("invalid audit entry on line %d: %w", line, err)
+		}
+
+		result := evaluator.Evaluate(hook.Input{
+			HookType:  entry.HookType,
+			ToolName:  entry.ToolName,
+			ToolInput: entry.ToolInput,
+		})
+
+		now := checkOutputFromResult(result).Decision
+		if now == entry.Decision {
+			unchanged++
+			continue
+		}
+		flipped++
+		fmt.Fprintf(out, "line %d: %s -> %s (%s)", line, entry.Decision, now, entry.ToolName)
+		if result.Reason != "" {
+			fmt.Fprintf(out, ": %s", result.Reason)
+		}
+		fmt.Fprintln(out)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("cannot read audit log: %w", err)
+	}
+
+	fmt.Fprintf(out, "%d replayed, %d flipped, %d unchanged\n", flipped+unchanged, flipped, unchanged)
+	return nil
+}
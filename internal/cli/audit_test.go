@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestRunAuditReplayFlips(t *testing.T) {
+	// The log was recorded under a config that allowed both writes; the
+	// current config blocks vendor, so the second decision flips.
+	cfg := &config.Config{
+		Rules: config.RulesConfig{Scope: true},
+		Scope: config.ScopeConfig{Block: []string{"vendor/**"}},
+	}
+
+	log := strings.Join([]string{
+		`{"tool_name":"Write","tool_input":{"file_path":"src/main.go"},"decision":"allow"}`,
+		`{"tool_name":"Write","tool_input":{"file_path":"vendor/lib.go"},"decision":"allow"}`,
+	}, "\n")
+
+	var out strings.Builder
+	if err := runAuditReplay(cfg, strings.NewReader(log), &out); err != nil {
+		t.Fatalf("runAuditReplay() error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "line 2: allow -> deny") {
+		t.Errorf("output should report the flipped decision, got:\n%s", got)
+	}
+	if strings.Contains(got, "line 1:") {
+		t.Errorf("unchanged decision should not be reported, got:\n%s", got)
+	}
+	if !strings.Contains(got, "2 replayed, 1 flipped, 1 unchanged") {
+		t.Errorf("summary line missing, got:\n%s", got)
+	}
+}
+
+func TestRunAuditReplayInvalidEntry(t *testing.T) {
+	cfg := &config.Config{}
+	var out strings.Builder
+	err := runAuditReplay(cfg, strings.NewReader("not json\n"), &out)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected invalid-entry error naming the line, got %v", err)
+	}
+}
+
+func TestRunAuditReplaySkipsBlankLines(t *testing.T) {
+	cfg := &config.Config{}
+	log := "\n" + `{"tool_name":"Read","tool_input":{"file_path":"a.go"},"decision":"allow"}` + "\n\n"
+
+	var out strings.Builder
+	if err := runAuditReplay(cfg, strings.NewReader(log), &out); err != nil {
+		t.Fatalf("runAuditReplay() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 replayed, 0 flipped, 1 unchanged") {
+		t.Errorf("summary = %q", out.String())
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/hook"
+)
+
+// CheckOutput is the machine-readable result of a check evaluation, intended
+// for CI assertions.
+type CheckOutput struct {
+	Decision string         `json:"decision"` // "allow", "deny" or "ask"
+	Code     string         `json:"code,omitempty"`
+	Rule     string         `json:"rule,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+	Warnings []hook.Warning `json:"warnings,omitempty"`
+}
+
+// RunCheck reads a hook input JSON from stdin, evaluates it, and reports the
+// result without the hook protocol's exit semantics. Format selects the
+// rendering: "json" (the default) for machine consumption, "yaml", or "text"
+// for human-readable lines. With strict, a result carrying any warning fails
+// even when allowed, for CI gating.
+func RunCheck(format string, strict bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("cannot load config: %w", err)
+	}
+	return runCheck(cfg, os.Stdin, os.Stdout, format, strict)
+}
+
+// runCheck is the testable core of RunCheck.
+func runCheck(cfg *config.Config, in io.Reader, out io.Writer, format string, strict bool) error {
+	var input fixtureInput
+	if err := json.NewDecoder(in).Decode(&input); err != nil {
+		return fmt.Errorf("invalid hook input: %w", err)
+	}
+
+	evaluator := hook.NewEvaluator(cfg)
+	result := evaluator.Evaluate(hook.Input{
+		HookType:  input.HookType,
+		ToolName:  input.ToolName,
+		ToolInput: input.ToolInput,
+	})
+
+	output := checkOutputFromResult(result)
+
+	if err := renderCheckOutput(out, output, format); err != nil {
+		return err
+	}
+
+	if strict && len(output.Warnings) > 0 {
+		return fmt.Errorf("strict-exit: %d warning(s)", len(output.Warnings))
+	}
+	return nil
+}
+
+// renderCheckOutput writes the result in the requested format.
+func renderCheckOutput(out io.Writer, output CheckOutput, format string) error {
+	switch format {
+	case "", "json":
+		return json.NewEncoder(out).Encode(output)
+	case "yaml":
+		data, err := yaml.Marshal(output)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	case "text":
+		fmt.Fprintf(out, "decision: %s\n", output.Decision)
+		if output.Reason != "" {
+			fmt.Fprintf(out, "reason: %s\n", output.Reason)
+		}
+		for _, w := range output.Warnings {
+			fmt.Fprintf(out, "warning: %s\n", w.Message)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format: %s (want text, json or yaml)", format)
+}
+
+// checkOutputFromResult converts an evaluator result to the output struct.
+func checkOutputFromResult(result hook.Result) CheckOutput {
+	output := CheckOutput{
+		Decision: "allow",
+		Code:     result.Code,
+		Rule:     hook.RuleForCode(result.Code),
+		Reason:   result.Reason,
+	}
+	if !result.Allowed {
+		output.Decision = "deny"
+		if result.Ask {
+			output.Decision = "ask"
+		}
+	}
+	output.Warnings = result.Warnings
+	return output
+}
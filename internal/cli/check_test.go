@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestRunCheckJSONOutOfScopeWrite(t *testing.T) {
+	cfg := &config.Config{
+		Rules: config.RulesConfig{Scope: true},
+		Scope: config.ScopeConfig{Allow: []string{"src/**"}},
+	}
+
+	in := strings.NewReader(`{"tool_name": "Write", "tool_input": {"file_path": "docs/out.md"}}`)
+	var out bytes.Buffer
+
+	if err := runCheck(cfg, in, &out, "json", false); err != nil {
+		t.Fatalf("runCheck failed: %v", err)
+	}
+
+	var output CheckOutput
+	if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if output.Decision != "deny" {
+		t.Errorf("decision = %q, want deny", output.Decision)
+	}
+	if output.Code != "scope" {
+		t.Errorf("code = %q, want scope", output.Code)
+	}
+	if output.Rule != "scope" {
+		t.Errorf("rule = %q, want scope", output.Rule)
+	}
+	if output.Reason == "" {
+		t.Error("expected a non-empty reason")
+	}
+}
+
+func TestRunCheckTextAllow(t *testing.T) {
+	cfg := &config.Config{}
+
+	in := strings.NewReader(`{"tool_name": "Read", "tool_input": {"file_path": "README.md"}}`)
+	var out bytes.Buffer
+
+	if err := runCheck(cfg, in, &out, "text", false); err != nil {
+		t.Fatalf("runCheck failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "decision: allow") {
+		t.Errorf("output = %q, want it to contain decision: allow", out.String())
+	}
+}
+
+func TestRunCheckInvalidInput(t *testing.T) {
+	cfg := &config.Config{}
+	if err := runCheck(cfg, strings.NewReader("not json"), &bytes.Buffer{}, "json", false); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
+
+func TestRunCheckYAMLRoundTrip(t *testing.T) {
+	cfg := &config.Config{
+		Rules: config.RulesConfig{Scope: true},
+		Scope: config.ScopeConfig{Allow: []string{"src/**"}},
+	}
+
+	in := strings.NewReader(`{"tool_name": "Write", "tool_input": {"file_path": "docs/out.md"}}`)
+	var yamlOut bytes.Buffer
+	if err := runCheck(cfg, in, &yamlOut, "yaml", false); err != nil {
+		t.Fatalf("runCheck failed: %v", err)
+	}
+
+	var fromYAML CheckOutput
+	if err := yaml.Unmarshal(yamlOut.Bytes(), &fromYAML); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+
+	in = strings.NewReader(`{"tool_name": "Write", "tool_input": {"file_path": "docs/out.md"}}`)
+	var jsonOut bytes.Buffer
+	if err := runCheck(cfg, in, &jsonOut, "json", false); err != nil {
+		t.Fatalf("runCheck failed: %v", err)
+	}
+	var fromJSON CheckOutput
+	if err := json.Unmarshal(jsonOut.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if fromYAML.Decision != fromJSON.Decision || fromYAML.Code != fromJSON.Code ||
+		fromYAML.Rule != fromJSON.Rule || fromYAML.Reason != fromJSON.Reason {
+		t.Errorf("YAML round trip = %+v, want %+v", fromYAML, fromJSON)
+	}
+	if fromYAML.Decision != "deny" || fromYAML.Code != "scope" {
+		t.Errorf("decision/code = %q/%q, want deny/scope", fromYAML.Decision, fromYAML.Code)
+	}
+}
+
+func TestRunCheckUnknownFormat(t *testing.T) {
+	cfg := &config.Config{}
+	in := strings.NewReader(`{"tool_name": "Read", "tool_input": {"file_path": "README.md"}}`)
+	if err := runCheck(cfg, in, &bytes.Buffer{}, "xml", false); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestRunCheckStrictExit(t *testing.T) {
+	// An enabled-but-empty scope rule produces a config warning on an
+	// otherwise allowed evaluation.
+	cfg := &config.Config{
+		Rules: config.RulesConfig{Scope: true},
+	}
+
+	input := `{"tool_name": "Read", "tool_input": {"file_path": "README.md"}}`
+
+	if err := runCheck(cfg, strings.NewReader(input), &bytes.Buffer{}, "text", false); err != nil {
+		t.Errorf("expected warnings to pass without --strict-exit: %v", err)
+	}
+
+	cfg = &config.Config{
+		Rules: config.RulesConfig{Scope: true},
+	}
+	if err := runCheck(cfg, strings.NewReader(input), &bytes.Buffer{}, "text", true); err == nil {
+		t.Error("expected warnings to fail under --strict-exit")
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+// RunConfigEffective prints the fully-resolved configuration in force,
+// including defaults, so surprising behavior can be traced to its source.
+func RunConfigEffective() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	return runConfigEffective(cfg, os.Stdout)
+}
+
+func runConfigEffective(cfg *config.Config, out io.Writer) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshal effective config: %w", err)
+	}
+	_, err = out.Write(data)
+	return err
+}
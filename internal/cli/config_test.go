@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestRunConfigEffectiveLocalOverride(t *testing.T) {
+	origHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", origHome)
+	tmpHome := t.TempDir()
+	os.Setenv("HOME", tmpHome)
+
+	globalDir := filepath.Join(tmpHome, ".config", "watchman")
+	if err := os.MkdirAll(globalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	global := "rules:\n  scope: true\nscope:\n  allow:\n    - \"docs/**\"\n"
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yml"), []byte(global), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	local := "rules:\n  workspace: true\n"
+	if err := os.WriteFile(".watchman.yml", []byte(local), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runConfigEffective(cfg, &out); err != nil {
+		t.Fatalf("runConfigEffective failed: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "workspace: true") {
+		t.Errorf("effective config %q should carry the local workspace rule", got)
+	}
+	// Local config wins over global; the global scope allow must not leak in.
+	if strings.Contains(got, "docs/**") {
+		t.Errorf("effective config %q should not include the shadowed global scope", got)
+	}
+}
+
+func TestRunConfigEffectiveDefaults(t *testing.T) {
+	var out bytes.Buffer
+	if err := runConfigEffective(config.Default(), &out); err != nil {
+		t.Fatalf("runConfigEffective failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "version:") {
+		t.Errorf("output %q should include the version field", out.String())
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/parser"
+	"github.com/adrianpk/watchman/internal/policy"
+)
+
+// ExplainEntry pairs a path candidate with its workspace verdict.
+type ExplainEntry struct {
+	Path    string `json:"path"`
+	Verdict string `json:"verdict"`
+}
+
+// RunExplain prints each path candidate collected from a Bash command and
+// its workspace verdict, so users can see which token trips a rule. Format
+// selects the rendering: "text" (the default), "json" or "yaml".
+func RunExplain(command, format string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("cannot load config: %w", err)
+	}
+	return runExplain(cfg, command, os.Stdout, format)
+}
+
+// runExplain is the testable core of RunExplain.
+func runExplain(cfg *config.Config, command string, out io.Writer, format string) error {
+	parsed := parser.Parse(command)
+	rule := policy.NewConfineToWorkspace(&cfg.Workspace)
+	rule.Protector = policy.NewProtector(&cfg.Protect)
+
+	entries := []ExplainEntry{}
+	for _, p := range policy.PathCandidates(parsed) {
+		entries = append(entries, ExplainEntry{Path: p, Verdict: rule.ExplainPath(p)})
+	}
+
+	switch format {
+	case "", "text":
+		if len(entries) == 0 {
+			fmt.Fprintln(out, "no path candidates found")
+			return nil
+		}
+		for _, e := range entries {
+			fmt.Fprintf(out, "%s: %s\n", e.Path, e.Verdict)
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(out).Encode(entries)
+	case "yaml":
+		data, err := yaml.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	return fmt.Errorf("unknown format: %s (want text, json or yaml)", format)
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestRunExplainCommand(t *testing.T) {
+	cfg := &config.Config{}
+	var out bytes.Buffer
+
+	if err := runExplain(cfg, "GOBIN=/x go test ../..", &out, "text"); err != nil {
+		t.Fatalf("runExplain failed: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "/x: outside workspace") {
+		t.Errorf("output %q should list /x as outside workspace", got)
+	}
+	if !strings.Contains(got, "../..: outside workspace (path traversal)") {
+		t.Errorf("output %q should flag ../.. as traversal", got)
+	}
+}
+
+func TestRunExplainJSONFormat(t *testing.T) {
+	cfg := &config.Config{}
+	var out bytes.Buffer
+
+	if err := runExplain(cfg, "cat /etc/passwd", &out, "json"); err != nil {
+		t.Fatalf("runExplain failed: %v", err)
+	}
+
+	var entries []ExplainEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Path != "/etc/passwd" || entries[0].Verdict != "outside workspace" {
+		t.Errorf("entry = %+v, want /etc/passwd outside workspace", entries[0])
+	}
+}
+
+func TestRunExplainUnknownFormat(t *testing.T) {
+	cfg := &config.Config{}
+	if err := runExplain(cfg, "ls", &bytes.Buffer{}, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestRunExplainNoCandidates(t *testing.T) {
+	cfg := &config.Config{}
+	var out bytes.Buffer
+
+	if err := runExplain(cfg, "ls", &out, "text"); err != nil {
+		t.Fatalf("runExplain failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "no path candidates") {
+		t.Errorf("output = %q, want no-candidates notice", out.String())
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/hook"
+)
+
+// fixtureInput mirrors the hook input JSON read from stdin.
+type fixtureInput struct {
+	HookType  string                 `json:"hook_type"`
+	ToolName  string                 `json:"tool_name"`
+	ToolInput map[string]interface{} `json:"tool_input"`
+}
+
+// fixtureExpectation is the optional sibling .expected file for a fixture.
+// Reason, when set, must be contained in the evaluator's reason.
+type fixtureExpectation struct {
+	Decision string `json:"decision"` // "allow" or "deny"
+	Reason   string `json:"reason,omitempty"`
+}
+
+// RunTestFixtures runs each .json hook input in dir through the evaluator,
+// comparing against sibling .expected files when present, and reports
+// pass/fail counts. Returns an error if any fixture fails.
+func RunTestFixtures(dir string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("cannot load config: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("cannot read fixtures directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	evaluator := hook.NewEvaluator(cfg)
+	pass, fail := 0, 0
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := runFixture(evaluator, path); err != nil {
+			fmt.Printf("FAIL %s: %v\n", name, err)
+			fail++
+		} else {
+			fmt.Printf("PASS %s\n", name)
+			pass++
+		}
+	}
+
+	fmt.Printf("%d passed, %d failed\n", pass, fail)
+	if fail > 0 {
+		return fmt.Errorf("%d fixture(s) failed", fail)
+	}
+	return nil
+}
+
+// runFixture evaluates one fixture file and checks its expectation, if any.
+func runFixture(evaluator *hook.Evaluator, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var input fixtureInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return fmt.Errorf("invalid fixture JSON: %w", err)
+	}
+
+	result := evaluator.Evaluate(hook.Input{
+		HookType:  input.HookType,
+		ToolName:  input.ToolName,
+		ToolInput: input.ToolInput,
+	})
+
+	expected, ok, err := loadExpectation(path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil // No expectation, running without error counts as pass
+	}
+
+	decision := "deny"
+	if result.Allowed {
+		decision = "allow"
+	}
+	if decision != expected.Decision {
+		return fmt.Errorf("decision = %s, want %s (reason: %s)", decision, expected.Decision, result.Reason)
+	}
+	if expected.Reason != "" && !strings.Contains(result.Reason, expected.Reason) {
+		return fmt.Errorf("reason = %q, want it to contain %q", result.Reason, expected.Reason)
+	}
+	return nil
+}
+
+// loadExpectation reads the sibling .expected file for a fixture, if present.
+func loadExpectation(fixturePath string) (fixtureExpectation, bool, error) {
+	expectedPath := strings.TrimSuffix(fixturePath, ".json") + ".expected"
+
+	data, err := os.ReadFile(expectedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fixtureExpectation{}, false, nil
+		}
+		return fixtureExpectation{}, false, err
+	}
+
+	var expected fixtureExpectation
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return fixtureExpectation{}, false, fmt.Errorf("invalid .expected JSON: %w", err)
+	}
+	return expected, true, nil
+}
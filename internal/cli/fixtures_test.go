@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunTestFixtures(t *testing.T) {
+	origWd, _ := os.Getwd()
+	defer os.Chdir(origWd)
+
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+
+	fixturesDir := filepath.Join(tmpDir, "fixtures")
+	if err := os.MkdirAll(fixturesDir, 0755); err != nil {
+		t.Fatalf("cannot create fixtures dir: %v", err)
+	}
+
+	// An allowed input: Read of a relative path
+	writeFixture(t, fixturesDir, "allow-read.json",
+		`{"tool_name": "Read", "tool_input": {"file_path": "main.go"}}`)
+	writeFixture(t, fixturesDir, "allow-read.expected",
+		`{"decision": "allow"}`)
+
+	// A denied input: Read of an absolute path outside the workspace
+	writeFixture(t, fixturesDir, "deny-read.json",
+		`{"tool_name": "Read", "tool_input": {"file_path": "/etc/passwd"}}`)
+	writeFixture(t, fixturesDir, "deny-read.expected",
+		`{"decision": "deny", "reason": "workspace"}`)
+
+	if err := RunTestFixtures(fixturesDir); err != nil {
+		t.Errorf("RunTestFixtures failed: %v", err)
+	}
+}
+
+func TestRunTestFixturesReportsFailure(t *testing.T) {
+	origWd, _ := os.Getwd()
+	defer os.Chdir(origWd)
+
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+
+	fixturesDir := filepath.Join(tmpDir, "fixtures")
+	if err := os.MkdirAll(fixturesDir, 0755); err != nil {
+		t.Fatalf("cannot create fixtures dir: %v", err)
+	}
+
+	// Expectation says deny but the input is allowed
+	writeFixture(t, fixturesDir, "wrong.json",
+		`{"tool_name": "Read", "tool_input": {"file_path": "main.go"}}`)
+	writeFixture(t, fixturesDir, "wrong.expected",
+		`{"decision": "deny"}`)
+
+	if err := RunTestFixtures(fixturesDir); err == nil {
+		t.Error("expected RunTestFixtures to report failure")
+	}
+}
+
+func writeFixture(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("cannot write fixture %s: %v", name, err)
+	}
+}
@@ -7,8 +7,18 @@ import (
 	"path/filepath"
 )
 
-// RunInit creates a watchman configuration file.
-func RunInit(local bool) error {
+// RunInit creates a watchman configuration file. A non-empty preset selects
+// a language-specific starter config instead of the generic default.
+func RunInit(local bool, preset string) error {
+	content := defaultConfig
+	if preset != "" {
+		var ok bool
+		content, ok = presetConfigs[preset]
+		if !ok {
+			return fmt.Errorf("unknown preset: %s (available: go, node, python)", preset)
+		}
+	}
+
 	var configPath string
 	var configDir string
 
@@ -38,7 +48,7 @@ func RunInit(local bool) error {
 		}
 	}
 
-	if err := os.WriteFile(configPath, []byte(defaultConfig), 0644); err != nil {
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("cannot write config: %w", err)
 	}
 
@@ -46,7 +56,7 @@ func RunInit(local bool) error {
 	return nil
 }
 
-const defaultConfig = `version: 1
+const defaultConfig = `version: 2
 
 rules:
   workspace: true
@@ -89,3 +99,114 @@ tools:
   allow: []
   block: []
 `
+
+// presetConfigs maps --preset names to starter configs with invariants
+// tailored to the ecosystem.
+var presetConfigs = map[string]string{
+	"go":     goPreset,
+	"node":   nodePreset,
+	"python": pythonPreset,
+}
+
+const goPreset = `version: 2
+
+rules:
+  workspace: true
+  scope: false
+  versioning: false
+  incremental: false
+  invariants: true
+
+workspace:
+  allow:
+    - /tmp/
+  block: []
+
+invariants:
+  coexistence:
+    - name: test-coexistence
+      if: "internal/**/*.go"
+      require: "${base}_test.go"
+      message: "Go source files should ship with a matching _test.go"
+  content:
+    - name: no-fmt-println
+      paths:
+        - "**/*.go"
+        - "!cmd/**"
+        - "!**/*_test.go"
+      forbid: 'fmt\.Println\('
+      message: "use a logger instead of fmt.Println outside package main"
+  naming:
+    - name: snake-case-filenames
+      paths:
+        - "**/*.go"
+      pattern: '^[a-z0-9_]+\.go$'
+      message: "Go filenames should be snake_case"
+  imports:
+    - name: no-replace-directive
+      paths:
+        - "go.mod"
+      forbid: '(?m)^replace '
+      message: "do not commit replace directives in go.mod"
+`
+
+const nodePreset = `version: 2
+
+rules:
+  workspace: true
+  scope: false
+  versioning: false
+  incremental: false
+  invariants: true
+
+workspace:
+  allow:
+    - /tmp/
+  block: []
+
+invariants:
+  content:
+    - name: no-console-log
+      paths:
+        - "src/**/*.js"
+        - "src/**/*.ts"
+        - "!**/*.test.*"
+      forbid: 'console\.log\('
+      message: "use a logger instead of console.log in src"
+  required:
+    - name: package-lock
+      dirs: "."
+      when: "package.json"
+      require: "package-lock.json"
+      message: "commit a lockfile alongside package.json"
+`
+
+const pythonPreset = `version: 2
+
+rules:
+  workspace: true
+  scope: false
+  versioning: false
+  incremental: false
+  invariants: true
+
+workspace:
+  allow:
+    - /tmp/
+  block: []
+
+invariants:
+  content:
+    - name: no-print
+      paths:
+        - "src/**/*.py"
+        - "!tests/**"
+      forbid: '(?m)^\s*print\('
+      message: "use logging instead of print in src"
+  naming:
+    - name: snake-case-modules
+      paths:
+        - "**/*.py"
+      pattern: '^[a-z0-9_]+\.py$'
+      message: "Python modules should be snake_case"
+`
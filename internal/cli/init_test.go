@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
 )
 
 func TestRunInitGlobal(t *testing.T) {
@@ -17,9 +19,9 @@ func TestRunInitGlobal(t *testing.T) {
 	os.Setenv("HOME", tmpHome)
 
 	// Run init
-	err := RunInit(false)
+	err := RunInit(false, "")
 	if err != nil {
-		t.Fatalf("RunInit(false) failed: %v", err)
+		t.Fatalf("RunInit(false, \"\") failed: %v", err)
 	}
 
 	// Verify config was created
@@ -34,7 +36,7 @@ func TestRunInitGlobal(t *testing.T) {
 		t.Fatalf("cannot read config: %v", err)
 	}
 
-	if !strings.Contains(string(content), "version: 1") {
+	if !strings.Contains(string(content), "version: 2") {
 		t.Error("config missing version")
 	}
 
@@ -57,9 +59,9 @@ func TestRunInitGlobalAlreadyExists(t *testing.T) {
 	os.WriteFile(configPath, []byte("existing"), 0644)
 
 	// Run init should not overwrite
-	err := RunInit(false)
+	err := RunInit(false, "")
 	if err != nil {
-		t.Fatalf("RunInit(false) failed: %v", err)
+		t.Fatalf("RunInit(false, \"\") failed: %v", err)
 	}
 
 	content, _ := os.ReadFile(configPath)
@@ -77,9 +79,9 @@ func TestRunInitLocal(t *testing.T) {
 	os.Chdir(tmpDir)
 
 	// Run init --local
-	err := RunInit(true)
+	err := RunInit(true, "")
 	if err != nil {
-		t.Fatalf("RunInit(true) failed: %v", err)
+		t.Fatalf("RunInit(true, \"\") failed: %v", err)
 	}
 
 	// Verify .watchman.yml was created
@@ -101,9 +103,9 @@ func TestRunInitLocalAlreadyExists(t *testing.T) {
 	os.WriteFile(configPath, []byte("existing"), 0644)
 
 	// Run init should not overwrite
-	err := RunInit(true)
+	err := RunInit(true, "")
 	if err != nil {
-		t.Fatalf("RunInit(true) failed: %v", err)
+		t.Fatalf("RunInit(true, \"\") failed: %v", err)
 	}
 
 	content, _ := os.ReadFile(configPath)
@@ -111,3 +113,49 @@ func TestRunInitLocalAlreadyExists(t *testing.T) {
 		t.Error("existing local config was overwritten")
 	}
 }
+
+func TestRunInitGoPreset(t *testing.T) {
+	origHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", origHome)
+	os.Setenv("HOME", t.TempDir())
+
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunInit(true, "go"); err != nil {
+		t.Fatalf("RunInit(true, \"go\") failed: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("go preset config does not parse: %v", err)
+	}
+
+	if !cfg.Rules.Invariants {
+		t.Error("go preset should enable the invariants rule")
+	}
+	if len(cfg.Invariants.Coexistence) == 0 {
+		t.Error("go preset should include a coexistence check")
+	}
+	if len(cfg.Invariants.Content) == 0 {
+		t.Error("go preset should include a content check")
+	}
+	if len(cfg.Invariants.Naming) == 0 {
+		t.Error("go preset should include a naming check")
+	}
+	if len(cfg.Invariants.Imports) == 0 {
+		t.Error("go preset should include an import check")
+	}
+}
+
+func TestRunInitUnknownPreset(t *testing.T) {
+	if err := RunInit(true, "rust"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+// migration upgrades a config document from one schema version to the next.
+type migration struct {
+	from int
+	note string
+	// apply transforms the raw document in place. Working on the generic
+	// map keeps keys the current schema no longer knows about visible to
+	// the migration.
+	apply func(doc map[string]interface{})
+}
+
+// migrations lists the known schema upgrades in order. Each entry moves a
+// document from its from version to from+1.
+var migrations = []migration{
+	{
+		from: 1,
+		note: "rename scope.patterns to scope.allow",
+		apply: func(doc map[string]interface{}) {
+			scope, ok := doc["scope"].(map[string]interface{})
+			if !ok {
+				return
+			}
+			patterns, ok := scope["patterns"]
+			if !ok {
+				return
+			}
+			if _, exists := scope["allow"]; !exists {
+				scope["allow"] = patterns
+			}
+			delete(scope, "patterns")
+		},
+	},
+}
+
+// RunConfigMigrate upgrades the active config file to the current schema
+// version, writing a .bak backup of the original first.
+func RunConfigMigrate() error {
+	path := config.ActiveConfigPath()
+	if path == "" {
+		return fmt.Errorf("no config file found; run 'watchman init' first")
+	}
+	return runConfigMigrate(path, os.Stdout)
+}
+
+// runConfigMigrate is the testable core of RunConfigMigrate.
+func runConfigMigrate(path string, out io.Writer) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read config: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("cannot parse config: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+
+	// A file without a version predates the field and is treated as v1.
+	version := 1
+	if v, ok := doc["version"].(int); ok && v > 0 {
+		version = v
+	}
+
+	if version >= config.CurrentVersion {
+		fmt.Fprintf(out, "config already at version %d\n", version)
+		return nil
+	}
+
+	backup := path + ".bak"
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return fmt.Errorf("cannot write backup: %w", err)
+	}
+	fmt.Fprintf(out, "backup written: %s\n", backup)
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		m.apply(doc)
+		fmt.Fprintf(out, "v%d -> v%d: %s\n", m.from, m.from+1, m.note)
+	}
+	doc["version"] = config.CurrentVersion
+
+	migrated, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("cannot marshal migrated config: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, 0644); err != nil {
+		return fmt.Errorf("cannot write config: %w", err)
+	}
+
+	fmt.Fprintf(out, "migrated %s to version %d\n", path, config.CurrentVersion)
+	return nil
+}
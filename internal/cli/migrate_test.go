@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestRunConfigMigrateV1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".watchman.yml")
+	original := "version: 1\nscope:\n  patterns:\n    - \"src/**\"\n    - \"internal/**\"\nrules:\n  scope: true\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runConfigMigrate(path, &out); err != nil {
+		t.Fatalf("runConfigMigrate failed: %v", err)
+	}
+
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config.Config
+	if err := yaml.Unmarshal(migrated, &cfg); err != nil {
+		t.Fatalf("migrated config is not valid YAML: %v", err)
+	}
+	if cfg.Version != config.CurrentVersion {
+		t.Errorf("version = %d, want %d", cfg.Version, config.CurrentVersion)
+	}
+	if len(cfg.Scope.Allow) != 2 || cfg.Scope.Allow[0] != "src/**" {
+		t.Errorf("scope.allow = %v, want patterns moved over", cfg.Scope.Allow)
+	}
+	if strings.Contains(string(migrated), "patterns") {
+		t.Error("deprecated scope.patterns key should be removed")
+	}
+	if !cfg.Rules.Scope {
+		t.Error("unrelated keys should survive migration")
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil {
+		t.Fatalf("backup missing: %v", err)
+	}
+	if string(backup) != original {
+		t.Error("backup should hold the original file content")
+	}
+}
+
+func TestRunConfigMigrateMissingVersionTreatedAsV1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".watchman.yml")
+	if err := os.WriteFile(path, []byte("scope:\n  patterns: [\"src/**\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runConfigMigrate(path, &bytes.Buffer{}); err != nil {
+		t.Fatalf("runConfigMigrate failed: %v", err)
+	}
+
+	var cfg config.Config
+	data, _ := os.ReadFile(path)
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != config.CurrentVersion {
+		t.Errorf("version = %d, want %d", cfg.Version, config.CurrentVersion)
+	}
+	if len(cfg.Scope.Allow) != 1 {
+		t.Errorf("scope.allow = %v, want migrated patterns", cfg.Scope.Allow)
+	}
+}
+
+func TestRunConfigMigrateAlreadyCurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".watchman.yml")
+	original := "version: 2\nscope:\n  allow: [\"src/**\"]\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runConfigMigrate(path, &out); err != nil {
+		t.Fatalf("runConfigMigrate failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "already at version") {
+		t.Errorf("output = %q, want already-current notice", out.String())
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != original {
+		t.Error("current config should be left untouched")
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("no backup should be written for a current config")
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/adrianpk/watchman/internal/state"
+)
+
+// RunScopeAdd records a session-scoped scope.allow pattern in the state file,
+// widening scope for the current session without editing committed config.
+func RunScopeAdd(pattern string) error {
+	mgr := state.NewManager()
+	if err := mgr.Load(); err != nil {
+		return fmt.Errorf("cannot load state: %w", err)
+	}
+
+	mgr.AddScopeAllow(pattern)
+	if err := mgr.Save(); err != nil {
+		return fmt.Errorf("cannot save state: %w", err)
+	}
+
+	fmt.Printf("Added session scope: %s\n", pattern)
+	return nil
+}
+
+// RunScopeClear removes all session-scoped scope.allow patterns.
+func RunScopeClear() error {
+	mgr := state.NewManager()
+	if err := mgr.Load(); err != nil {
+		return fmt.Errorf("cannot load state: %w", err)
+	}
+
+	mgr.ClearScopeAllow()
+	if err := mgr.Save(); err != nil {
+		return fmt.Errorf("cannot save state: %w", err)
+	}
+
+	fmt.Println("Cleared session scope")
+	return nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/adrianpk/watchman/internal/hook"
+	"github.com/adrianpk/watchman/internal/state"
+)
+
+// RunStats prints the per-rule denial tallies recorded in the state file,
+// so frequently firing rules can be identified and the config tuned.
+func RunStats() error {
+	mgr := state.NewManager()
+	if err := mgr.Load(); err != nil {
+		return fmt.Errorf("cannot load state: %w", err)
+	}
+	return runStats(mgr, os.Stdout)
+}
+
+func runStats(mgr *state.Manager, out io.Writer) error {
+	counts := mgr.DenyCounts()
+	if len(counts) == 0 {
+		fmt.Fprintln(out, "no denials recorded")
+		return nil
+	}
+
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if counts[codes[i]] != counts[codes[j]] {
+			return counts[codes[i]] > counts[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+
+	for _, code := range codes {
+		rule := hook.RuleForCode(code)
+		if rule == "" {
+			rule = "unknown"
+		}
+		fmt.Fprintf(out, "%s (%s): %d\n", code, rule, counts[code])
+	}
+	return nil
+}
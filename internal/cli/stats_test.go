@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adrianpk/watchman/internal/state"
+)
+
+func TestRunStatsEmpty(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := state.NewManager()
+	if err := mgr.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runStats(mgr, &out); err != nil {
+		t.Fatalf("runStats failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "no denials recorded") {
+		t.Errorf("output = %q, want empty-state notice", out.String())
+	}
+}
+
+func TestRunStatsOrdering(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := state.NewManager()
+	if err := mgr.Load(); err != nil {
+		t.Fatal(err)
+	}
+	mgr.RecordDeny("scope")
+	mgr.RecordDeny("scope")
+	mgr.RecordDeny("workspace")
+
+	var out bytes.Buffer
+	if err := runStats(mgr, &out); err != nil {
+		t.Fatalf("runStats failed: %v", err)
+	}
+
+	got := out.String()
+	scopeIdx := strings.Index(got, "scope (scope): 2")
+	wsIdx := strings.Index(got, "workspace (workspace): 1")
+	if scopeIdx == -1 || wsIdx == -1 {
+		t.Fatalf("output missing expected counters: %q", got)
+	}
+	if scopeIdx > wsIdx {
+		t.Errorf("highest count should come first: %q", got)
+	}
+}
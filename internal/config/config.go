@@ -2,8 +2,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,7 +13,11 @@ import (
 
 // Config represents the watchman configuration.
 type Config struct {
-	Version     int               `yaml:"version"`
+	Version int `yaml:"version"`
+	// Mode optionally switches the whole evaluator into a coarse operating
+	// mode. "readonly" denies every modification tool and mutating Bash
+	// command, for review/analysis sessions.
+	Mode        string            `yaml:"mode,omitempty"`
 	Rules       RulesConfig       `yaml:"rules"`
 	Workspace   WorkspaceConfig   `yaml:"workspace"`
 	Scope       ScopeConfig       `yaml:"scope"`
@@ -19,9 +25,31 @@ type Config struct {
 	Incremental IncrementalConfig `yaml:"incremental"`
 	Invariants  InvariantsConfig  `yaml:"invariants,omitempty"`
 	Commands    CommandsConfig    `yaml:"commands"`
+	Protect     ProtectConfig     `yaml:"protect,omitempty"`
+	Read        ReadConfig        `yaml:"read,omitempty"`
+	Git         GitConfig         `yaml:"git"`
 	Tools       ToolsConfig       `yaml:"tools"`
 	Hooks       []HookConfig      `yaml:"hooks,omitempty"`
 	Reminders   []ReminderConfig  `yaml:"reminders,omitempty"`
+	Exceptions  []ExceptionConfig `yaml:"exceptions,omitempty"`
+	// Messages maps decision codes to custom deny-message templates.
+	// Templates may use {path}, {rule} and {reason} placeholders; codes
+	// without a template keep the built-in reason.
+	Messages map[string]string `yaml:"messages,omitempty"`
+	// MaxInputBytes denies hook inputs whose stdin JSON exceeds this size,
+	// before decoding. 0 disables the limit.
+	MaxInputBytes int `yaml:"max_input_bytes,omitempty"`
+	// DefaultWarnRatio is the warn ratio applied when a rule's own ratio
+	// is unset, so org-wide warn behavior is set once. 0 keeps the
+	// built-in 70% default.
+	DefaultWarnRatio float64 `yaml:"default_warn_ratio,omitempty"`
+}
+
+// ExceptionConfig is a narrow tool+path pair that bypasses rule evaluation
+// for an exact match.
+type ExceptionConfig struct {
+	Tool string `yaml:"tool"`
+	Path string `yaml:"path"`
 }
 
 // RulesConfig enables/disables semantic rules.
@@ -33,18 +61,45 @@ type RulesConfig struct {
 	Invariants  bool `yaml:"invariants"`
 	Patterns    bool `yaml:"patterns"`
 	Boundaries  bool `yaml:"boundaries"`
+	// RequireTests warns when a non-test .go file is written without a
+	// corresponding _test.go companion existing or being created.
+	RequireTests bool `yaml:"require_tests,omitempty"`
 }
 
 // WorkspaceConfig controls the workspace confinement rule.
 type WorkspaceConfig struct {
 	Allow []string `yaml:"allow"`
 	Block []string `yaml:"block"`
+	// StripRootPrefix normalizes relative paths that repeat the workspace
+	// directory name (e.g. proj/src/x.go written from inside proj/).
+	StripRootPrefix bool `yaml:"strip_root_prefix,omitempty"`
+	// BlockWorktrees denies git worktree creation entirely. Without it,
+	// worktrees are only denied when their path leaves the workspace.
+	BlockWorktrees bool `yaml:"block_worktrees,omitempty"`
+	// OnGetwdError selects the policy when the working directory cannot be
+	// determined: "fail_closed" (default) denies unresolvable relative
+	// paths, "fail_open" allows them.
+	OnGetwdError string `yaml:"on_getwd_error,omitempty"`
 }
 
 // ScopeConfig controls which files can be modified.
 type ScopeConfig struct {
 	Allow []string `yaml:"allow"`
 	Block []string `yaml:"block"`
+	// GoPackages scopes by Go package patterns (./internal/..., ./cmd/foo)
+	// resolved against the module root, as an alternative to file globs.
+	GoPackages []string `yaml:"go_packages,omitempty"`
+	// TrackedOnly denies modifications to paths not present in the git
+	// index, keeping the model from scattering new files.
+	TrackedOnly bool `yaml:"tracked_only,omitempty"`
+	// NewFilesOnly limits scope evaluation to paths that do not exist
+	// yet, so new-file creation is confined while edits to existing files
+	// pass unrestricted.
+	NewFilesOnly bool `yaml:"new_files_only,omitempty"`
+	// Exempt lists globs that are always writable regardless of the
+	// allow/block evaluation (generated files, for example). Exempt paths
+	// are still subject to workspace confinement and path protection.
+	Exempt []string `yaml:"exempt,omitempty"`
 }
 
 // VersioningConfig controls commit and branch rules.
@@ -54,11 +109,31 @@ type VersioningConfig struct {
 	Operations OperationsConfig `yaml:"operations"`
 	Workflow   string           `yaml:"workflow"`
 	Tool       string           `yaml:"tool"`
+	// ToolPolicy softens or hardens the tool preference: "prefer" warns
+	// instead of denying git commit, "require" denies every mutating git
+	// subcommand when jj is the chosen tool. Empty keeps the default of
+	// denying only git commit.
+	ToolPolicy string `yaml:"tool_policy,omitempty"`
+	// AllowInlineOverride lets a trailing "# watchman:force" comment bypass
+	// the operation block for a single command.
+	AllowInlineOverride bool `yaml:"allow_inline_override,omitempty"`
+	// ForbidNoVerify denies git commit/push commands that skip local git
+	// hooks via --no-verify.
+	ForbidNoVerify bool `yaml:"forbid_no_verify,omitempty"`
+	// AllowedRemotes restricts which remotes git push may target. Empty
+	// allows all; pushes without an explicit remote are not checked.
+	AllowedRemotes []string `yaml:"allowed_remotes,omitempty"`
+	// RestrictAddScope denies mass staging (git add -A, git add .) that
+	// would stage files outside the configured scope rules.
+	RestrictAddScope bool `yaml:"restrict_add_scope,omitempty"`
 }
 
 // CommitConfig controls commit message validation.
 type CommitConfig struct {
-	MaxLength        int    `yaml:"max_length"`
+	MaxLength int `yaml:"max_length"`
+	// MinLength denies commit subjects shorter than N characters after
+	// trimming whitespace. Zero disables the check.
+	MinLength        int    `yaml:"min_length,omitempty"`
 	MaxFiles         int    `yaml:"max_files"`
 	RequireUppercase bool   `yaml:"require_uppercase"`
 	NoPeriod         bool   `yaml:"no_period"`
@@ -67,6 +142,14 @@ type CommitConfig struct {
 	ForbidColons     bool   `yaml:"forbid_colons"`
 	Conventional     bool   `yaml:"conventional"`
 	PrefixPattern    string `yaml:"prefix_pattern"`
+	RequireSigned    bool   `yaml:"require_signed,omitempty"`
+	// AllowedScopes restricts the conventional-commit scope, so with
+	// ["api","ui"] a "feat(api):" subject passes and "feat(random):" is
+	// denied. Empty disables the check.
+	AllowedScopes []string `yaml:"allowed_scopes,omitempty"`
+	// RequireScope additionally denies commits whose subject carries no
+	// scope at all. Only meaningful when AllowedScopes is set.
+	RequireScope bool `yaml:"require_scope,omitempty"`
 }
 
 // OperationsConfig controls blocked git operations.
@@ -77,23 +160,120 @@ type OperationsConfig struct {
 // BranchesConfig controls branch protection.
 type BranchesConfig struct {
 	Protected []string `yaml:"protected"`
+	// OnProtected selects what happens on a protected-branch commit:
+	// "deny" (default) fails outright, "ask" prompts the user.
+	OnProtected string `yaml:"on_protected,omitempty"`
 }
 
 // IncrementalConfig controls change size limits.
 type IncrementalConfig struct {
-	MaxFiles  int     `yaml:"max_files"`
-	WarnRatio float64 `yaml:"warn_ratio"`
+	MaxFiles          int     `yaml:"max_files"`
+	WarnRatio         float64 `yaml:"warn_ratio"`
+	SessionByteBudget int     `yaml:"session_byte_budget,omitempty"`
 }
 
 // CommandsConfig controls shell command filtering.
 type CommandsConfig struct {
-	Block []string `yaml:"block"`
+	Block            []string `yaml:"block"`
+	BlockEncodedExec bool     `yaml:"block_encoded_exec,omitempty"`
+	// BlockNetwork denies known network tools in command position.
+	// NetworkAllow lists tools exempt from the category block.
+	BlockNetwork bool     `yaml:"block_network,omitempty"`
+	NetworkAllow []string `yaml:"network_allow,omitempty"`
+	// BlockEval denies eval in command position, a common obfuscation
+	// vector for dynamically constructed commands.
+	BlockEval bool `yaml:"block_eval,omitempty"`
+	// BlockInstallScripts denies package-manager installs that could run
+	// install-time scripts unless a scripts-disabling flag is present.
+	BlockInstallScripts bool `yaml:"block_install_scripts,omitempty"`
+	// EnvAllow, when non-empty, restricts which environment variables a
+	// command may set via leading VAR=value assignments.
+	EnvAllow []string `yaml:"env_allow,omitempty"`
+	// BlockDangerousEnv denies library-injection variables (LD_PRELOAD,
+	// LD_LIBRARY_PATH, PATH) regardless of the allowlist.
+	BlockDangerousEnv bool `yaml:"block_dangerous_env,omitempty"`
+}
+
+// ReadConfig restricts what non-modifying filesystem tools (Read, Grep,
+// Glob) may access, independent of the write-oriented scope rule.
+type ReadConfig struct {
+	// Block lists path globs that must never be read, even inside the
+	// workspace (e.g. .env, secrets/**).
+	Block []string `yaml:"block,omitempty"`
+}
+
+// ProtectConfig defines additional protection settings beyond the hardcoded paths.
+type ProtectConfig struct {
+	Categories    []ProtectCategory `yaml:"categories,omitempty"`
+	BlockDataLoss bool              `yaml:"block_data_loss,omitempty"`
+	// MaxDeleteDepth denies recursive deletes targeting directories fewer
+	// than this many levels below the workspace root. 0 disables the guard.
+	MaxDeleteDepth int `yaml:"max_delete_depth,omitempty"`
+	// ScanSecrets denies writes whose content looks like a credential
+	// (AWS key IDs, private key headers). ScanSecretsAllow lists path
+	// globs exempt from the scan (e.g. test fixtures).
+	ScanSecrets      bool     `yaml:"scan_secrets,omitempty"`
+	ScanSecretsAllow []string `yaml:"scan_secrets_allow,omitempty"`
+	// ConfigFilename controls the filename-based protection of .watchman.yml
+	// in any directory. Unset means enabled; explicit false disables only the
+	// filename rule, leaving path-based protection intact.
+	ConfigFilename *bool `yaml:"config_filename,omitempty"`
+	// Extra lists additional always-protected paths appended to the
+	// hardcoded defaults. Entries follow the same ~/ and trailing-slash
+	// conventions as the built-in list.
+	Extra []string `yaml:"extra,omitempty"`
+	// NoOverwrite lists globs where creating a new file is allowed but
+	// overwriting an existing one is denied (e.g. applied migrations).
+	NoOverwrite []string `yaml:"no_overwrite,omitempty"`
+	// BlockSetuid denies chmod/install invocations that set setuid or
+	// setgid bits.
+	BlockSetuid bool `yaml:"block_setuid,omitempty"`
+	// BlockRecursivePerms denies recursive chmod/chown/chgrp invocations
+	// (-R/--recursive), which almost always target whole directories.
+	BlockRecursivePerms bool `yaml:"block_recursive_perms,omitempty"`
+	// BlockExfil denies pipelines that read secrets (env dumps, credential
+	// files) and feed them into a network command.
+	BlockExfil bool `yaml:"block_exfil,omitempty"`
+	// Infra guards CI/CD and deployment files (workflows, Dockerfiles,
+	// Terraform, k8s manifests) against modification. OnInfra selects the
+	// outcome: "deny" (default) fails outright, "ask" prompts the user.
+	// InfraPaths overrides the built-in glob list.
+	Infra      bool     `yaml:"infra,omitempty"`
+	OnInfra    string   `yaml:"on_infra,omitempty"`
+	InfraPaths []string `yaml:"infra_paths,omitempty"`
+	// Self denies commands that tamper with watchman itself: setting
+	// WATCHMAN_DISABLE, rewriting the Claude settings hook, or stripping
+	// the execute bit from the watchman binary.
+	Self bool `yaml:"self,omitempty"`
+}
+
+// ProtectCategory is a named group of protected path patterns with a custom deny message.
+type ProtectCategory struct {
+	Name     string   `yaml:"name"`
+	Patterns []string `yaml:"patterns"`
+	Message  string   `yaml:"message,omitempty"`
+}
+
+// GitConfig controls how git-dependent checks behave.
+type GitConfig struct {
+	OnError string `yaml:"on_error,omitempty"` // "deny" to fail closed when git is unavailable
 }
 
 // ToolsConfig controls which tools are available.
 type ToolsConfig struct {
 	Allow []string `yaml:"allow"`
 	Block []string `yaml:"block"`
+	// PathAllow maps a tool name to paths/globs that bypass workspace
+	// confinement for that tool only.
+	PathAllow map[string][]string `yaml:"path_allow,omitempty"`
+	// Filesystem and Modifying extend the built-in tool classification so
+	// custom tools are subject to filesystem and modification rules.
+	Filesystem []string `yaml:"filesystem,omitempty"`
+	Modifying  []string `yaml:"modifying,omitempty"`
+	// Aliases maps external tool names to their canonical equivalents
+	// (e.g. str_replace_editor: Edit), so renamed tools keep the built-in
+	// classification and rules.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
 }
 
 // HookConfig defines an external hook executable.
@@ -105,6 +285,10 @@ type HookConfig struct {
 	Paths   []string      `yaml:"paths,omitempty"`
 	Timeout time.Duration `yaml:"timeout,omitempty"`
 	OnError string        `yaml:"on_error,omitempty"`
+	// Workdir is the directory the hook runs in. Relative paths resolve
+	// against the current working directory and must stay within the
+	// workspace. Empty runs the hook from the current directory.
+	Workdir string `yaml:"workdir,omitempty"`
 }
 
 // ReminderConfig defines a periodic reminder to show the agent.
@@ -166,21 +350,38 @@ type RequiredCheck struct {
 	Message string `yaml:"message,omitempty"`
 }
 
+// CurrentVersion is the config schema version new configs are written with.
+// Version 2 renamed scope.patterns to scope.allow; watchman config migrate
+// upgrades older files.
+const CurrentVersion = 2
+
 // Default returns the default configuration.
 func Default() *Config {
 	return &Config{
-		Version: 1,
+		Version: CurrentVersion,
 		Rules: RulesConfig{
 			Workspace: true,
 		},
 	}
 }
 
-// Load loads configuration. If local config exists, it is used exclusively.
-// Otherwise, global config is used. No merging occurs.
+// configEnvVar points at an exact config file, bypassing discovery.
+const configEnvVar = "WATCHMAN_CONFIG"
+
+// Load loads configuration. An explicit WATCHMAN_CONFIG path wins over
+// discovery. Otherwise, if local config exists, it is used exclusively;
+// otherwise global config is used. No merging occurs.
 func Load() (*Config, error) {
 	cfg := Default()
 
+	// Explicit config path from the environment, for CI/containerized runs
+	if envPath := os.Getenv(configEnvVar); envPath != "" {
+		if err := cfg.loadFrom(envPath); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+
 	// Check for local config first - if exists, use only local
 	localPath := localConfigPath()
 	if localPath != "" {
@@ -226,20 +427,67 @@ func (c *Config) merge(overlay *Config) {
 	if overlay.Version > 0 {
 		c.Version = overlay.Version
 	}
+	if overlay.MaxInputBytes > 0 {
+		c.MaxInputBytes = overlay.MaxInputBytes
+	}
+	if overlay.DefaultWarnRatio > 0 {
+		c.DefaultWarnRatio = overlay.DefaultWarnRatio
+	}
+	if overlay.Mode != "" {
+		c.Mode = overlay.Mode
+	}
 	c.Rules = overlay.Rules
-	c.Workspace.Allow = appendUnique(c.Workspace.Allow, overlay.Workspace.Allow)
-	c.Workspace.Block = appendUnique(c.Workspace.Block, overlay.Workspace.Block)
-	c.Scope.Allow = appendUnique(c.Scope.Allow, overlay.Scope.Allow)
-	c.Scope.Block = appendUnique(c.Scope.Block, overlay.Scope.Block)
+	c.Workspace.Allow = appendUnique(normalizePaths(c.Workspace.Allow), normalizePaths(overlay.Workspace.Allow))
+	c.Workspace.Block = appendUnique(normalizePaths(c.Workspace.Block), normalizePaths(overlay.Workspace.Block))
+	c.Workspace.StripRootPrefix = overlay.Workspace.StripRootPrefix
+	c.Scope.Allow = appendUnique(normalizeGlobs(c.Scope.Allow), normalizeGlobs(overlay.Scope.Allow))
+	c.Scope.Block = appendUnique(normalizeGlobs(c.Scope.Block), normalizeGlobs(overlay.Scope.Block))
+	c.Scope.GoPackages = appendUnique(c.Scope.GoPackages, overlay.Scope.GoPackages)
+	c.Scope.TrackedOnly = overlay.Scope.TrackedOnly
+	c.Scope.NewFilesOnly = overlay.Scope.NewFilesOnly
+	c.Scope.Exempt = appendUnique(normalizeGlobs(c.Scope.Exempt), normalizeGlobs(overlay.Scope.Exempt))
 	c.Versioning = overlay.Versioning
 	c.Versioning.Branches.Protected = appendUnique(c.Versioning.Branches.Protected, overlay.Versioning.Branches.Protected)
+	c.Versioning.AllowedRemotes = appendUnique(c.Versioning.AllowedRemotes, overlay.Versioning.AllowedRemotes)
 	c.Incremental = overlay.Incremental
 	c.Invariants = mergeInvariants(c.Invariants, overlay.Invariants)
 	c.Commands.Block = appendUnique(c.Commands.Block, overlay.Commands.Block)
+	c.Commands.BlockEncodedExec = overlay.Commands.BlockEncodedExec
+	c.Commands.BlockNetwork = overlay.Commands.BlockNetwork
+	c.Commands.NetworkAllow = appendUnique(c.Commands.NetworkAllow, overlay.Commands.NetworkAllow)
+	c.Commands.BlockEval = overlay.Commands.BlockEval
+	c.Commands.BlockInstallScripts = overlay.Commands.BlockInstallScripts
+	c.Commands.EnvAllow = appendUnique(c.Commands.EnvAllow, overlay.Commands.EnvAllow)
+	c.Commands.BlockDangerousEnv = overlay.Commands.BlockDangerousEnv
+	c.Protect.Categories = appendCategoriesUnique(c.Protect.Categories, overlay.Protect.Categories)
+	c.Protect.BlockDataLoss = overlay.Protect.BlockDataLoss
+	c.Protect.MaxDeleteDepth = overlay.Protect.MaxDeleteDepth
+	c.Protect.ScanSecrets = overlay.Protect.ScanSecrets
+	c.Protect.ScanSecretsAllow = appendUnique(c.Protect.ScanSecretsAllow, overlay.Protect.ScanSecretsAllow)
+	if overlay.Protect.ConfigFilename != nil {
+		c.Protect.ConfigFilename = overlay.Protect.ConfigFilename
+	}
+	c.Protect.Extra = appendUnique(c.Protect.Extra, overlay.Protect.Extra)
+	c.Protect.NoOverwrite = appendUnique(c.Protect.NoOverwrite, overlay.Protect.NoOverwrite)
+	c.Protect.BlockSetuid = overlay.Protect.BlockSetuid
+	c.Protect.BlockRecursivePerms = overlay.Protect.BlockRecursivePerms
+	c.Protect.Self = overlay.Protect.Self
+	c.Protect.Infra = overlay.Protect.Infra
+	c.Protect.OnInfra = overlay.Protect.OnInfra
+	c.Protect.InfraPaths = appendUnique(c.Protect.InfraPaths, overlay.Protect.InfraPaths)
+	c.Protect.BlockExfil = overlay.Protect.BlockExfil
+	c.Read.Block = appendUnique(c.Read.Block, overlay.Read.Block)
+	c.Git = overlay.Git
 	c.Tools.Allow = appendUnique(c.Tools.Allow, overlay.Tools.Allow)
 	c.Tools.Block = appendUnique(c.Tools.Block, overlay.Tools.Block)
+	c.Tools.PathAllow = mergePathAllow(c.Tools.PathAllow, overlay.Tools.PathAllow)
+	c.Messages = mergeStringMap(c.Messages, overlay.Messages)
+	c.Tools.Filesystem = appendUnique(c.Tools.Filesystem, overlay.Tools.Filesystem)
+	c.Tools.Aliases = mergeStringMap(c.Tools.Aliases, overlay.Tools.Aliases)
+	c.Tools.Modifying = appendUnique(c.Tools.Modifying, overlay.Tools.Modifying)
 	c.Hooks = appendHooksUnique(c.Hooks, overlay.Hooks)
 	c.Reminders = appendRemindersUnique(c.Reminders, overlay.Reminders)
+	c.Exceptions = appendExceptionsUnique(c.Exceptions, overlay.Exceptions)
 }
 
 func mergeInvariants(base, overlay InvariantsConfig) InvariantsConfig {
@@ -327,6 +575,47 @@ func appendRequiredUnique(base, items []RequiredCheck) []RequiredCheck {
 	return result
 }
 
+func mergePathAllow(base, overlay map[string][]string) map[string][]string {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = make(map[string][]string)
+	}
+	for tool, paths := range overlay {
+		base[tool] = appendUnique(base[tool], paths)
+	}
+	return base
+}
+
+func mergeStringMap(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+	if base == nil {
+		base = make(map[string]string)
+	}
+	for code, template := range overlay {
+		base[code] = template
+	}
+	return base
+}
+
+func appendCategoriesUnique(base, items []ProtectCategory) []ProtectCategory {
+	seen := make(map[string]bool)
+	for _, c := range base {
+		seen[c.Name] = true
+	}
+	result := base
+	for _, c := range items {
+		if !seen[c.Name] {
+			result = append(result, c)
+			seen[c.Name] = true
+		}
+	}
+	return result
+}
+
 func appendHooksUnique(base, items []HookConfig) []HookConfig {
 	seen := make(map[string]bool)
 	for _, h := range base {
@@ -342,6 +631,21 @@ func appendHooksUnique(base, items []HookConfig) []HookConfig {
 	return result
 }
 
+func appendExceptionsUnique(base, items []ExceptionConfig) []ExceptionConfig {
+	seen := make(map[string]bool)
+	for _, e := range base {
+		seen[e.Tool+"\x00"+e.Path] = true
+	}
+	result := base
+	for _, e := range items {
+		if !seen[e.Tool+"\x00"+e.Path] {
+			result = append(result, e)
+			seen[e.Tool+"\x00"+e.Path] = true
+		}
+	}
+	return result
+}
+
 func appendRemindersUnique(base, items []ReminderConfig) []ReminderConfig {
 	seen := make(map[string]bool)
 	for _, r := range base {
@@ -357,6 +661,27 @@ func appendRemindersUnique(base, items []ReminderConfig) []ReminderConfig {
 	return result
 }
 
+// normalizePaths canonicalizes path list entries so near-duplicates merge
+// predictably: ~/ is expanded and trailing slashes are trimmed, collapsing
+// /tmp and /tmp/ to one entry.
+func normalizePaths(paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		if strings.HasPrefix(p, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				p = filepath.Join(home, p[2:])
+			}
+		}
+		if len(p) > 1 {
+			p = strings.TrimRight(p, "/")
+		}
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
 func appendUnique(base, items []string) []string {
 	seen := make(map[string]bool)
 	for _, s := range base {
@@ -372,12 +697,37 @@ func appendUnique(base, items []string) []string {
 	return result
 }
 
+// normalizeGlobs canonicalizes glob list entries so near-duplicates from
+// different config files merge predictably: a leading "./" and trailing
+// slashes are trimmed, collapsing ./src/** and src/**/ into src/**. Merge
+// order stays base-first, so equal entries land where the base put them.
+func normalizeGlobs(globs []string) []string {
+	var out []string
+	for _, g := range globs {
+		g = strings.TrimPrefix(g, "./")
+		if len(g) > 1 {
+			g = strings.TrimRight(g, "/")
+		}
+		if g != "" {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// localConfigNames and globalConfigNames list the accepted config spellings,
+// in preference order: .yml wins when both are present.
+var (
+	localConfigNames  = []string{".watchman.yml", ".watchman.yaml"}
+	globalConfigNames = []string{"config.yml", "config.yaml"}
+)
+
 func globalConfigPath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return ""
 	}
-	return filepath.Join(home, ".config", "watchman", "config.yml")
+	return firstConfigPath(filepath.Join(home, ".config", "watchman"), globalConfigNames)
 }
 
 // GlobalConfigPath returns the path to the global config file.
@@ -385,10 +735,51 @@ func GlobalConfigPath() string {
 	return globalConfigPath()
 }
 
+// ActiveConfigPath returns the config file Load would read: the explicit
+// WATCHMAN_CONFIG path, else an existing local config, else the global
+// config location. It returns "" when no config file applies.
+func ActiveConfigPath() string {
+	if envPath := os.Getenv(configEnvVar); envPath != "" {
+		return envPath
+	}
+	if localPath := localConfigPath(); localPath != "" {
+		if _, err := os.Stat(localPath); err == nil {
+			return localPath
+		}
+	}
+	globalPath := globalConfigPath()
+	if globalPath != "" {
+		if _, err := os.Stat(globalPath); err == nil {
+			return globalPath
+		}
+	}
+	return ""
+}
+
 func localConfigPath() string {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return ""
 	}
-	return filepath.Join(cwd, ".watchman.yml")
+	return firstConfigPath(cwd, localConfigNames)
+}
+
+// firstConfigPath returns the first existing candidate in dir, warning when
+// more than one spelling is present. When none exists it returns the
+// preferred spelling so callers report a consistent path.
+func firstConfigPath(dir string, names []string) string {
+	var found []string
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			found = append(found, p)
+		}
+	}
+	if len(found) == 0 {
+		return filepath.Join(dir, names[0])
+	}
+	if len(found) > 1 {
+		fmt.Fprintf(os.Stderr, "warning: both %s and %s exist; using %s\n", found[0], found[1], found[0])
+	}
+	return found[0]
 }
@@ -9,8 +9,8 @@ import (
 func TestDefault(t *testing.T) {
 	cfg := Default()
 
-	if cfg.Version != 1 {
-		t.Errorf("Version = %d, want 1", cfg.Version)
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
 	}
 	if !cfg.Rules.Workspace {
 		t.Error("Rules.Workspace should be true by default")
@@ -243,3 +243,181 @@ func TestLocalConfigPath(t *testing.T) {
 		t.Errorf("localConfigPath = %s, want %s", path, expected)
 	}
 }
+
+func TestLoadFromEnvVar(t *testing.T) {
+	origWd, _ := os.Getwd()
+	defer os.Chdir(origWd)
+
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+
+	// A local config that would normally win
+	localConfig := []byte("version: 1\nincremental:\n  max_files: 5\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, ".watchman.yml"), localConfig, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The env-pointed config must take precedence
+	envConfig := []byte("version: 1\nincremental:\n  max_files: 42\n")
+	envPath := filepath.Join(tmpDir, "ci-config.yml")
+	if err := os.WriteFile(envPath, envConfig, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(configEnvVar, envPath)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Incremental.MaxFiles != 42 {
+		t.Errorf("MaxFiles = %d, want 42 (env config should win over local)", cfg.Incremental.MaxFiles)
+	}
+}
+
+func TestLoadFromEnvVarUnreadable(t *testing.T) {
+	t.Setenv(configEnvVar, filepath.Join(t.TempDir(), "missing.yml"))
+
+	if _, err := Load(); err == nil {
+		t.Error("Load() should fail when WATCHMAN_CONFIG points at an unreadable file")
+	}
+}
+
+func TestLocalConfigPathYamlSpelling(t *testing.T) {
+	origWd, _ := os.Getwd()
+	defer os.Chdir(origWd)
+
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+
+	// No config at all: the preferred .yml spelling is reported
+	if got, want := localConfigPath(), filepath.Join(tmpDir, ".watchman.yml"); got != want {
+		t.Errorf("localConfigPath() = %s, want %s", got, want)
+	}
+
+	// Only .yaml exists: it is discovered
+	yamlPath := filepath.Join(tmpDir, ".watchman.yaml")
+	if err := os.WriteFile(yamlPath, []byte("version: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := localConfigPath(); got != yamlPath {
+		t.Errorf("localConfigPath() = %s, want %s", got, yamlPath)
+	}
+
+	// Both exist: .yml wins
+	ymlPath := filepath.Join(tmpDir, ".watchman.yml")
+	if err := os.WriteFile(ymlPath, []byte("version: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := localConfigPath(); got != ymlPath {
+		t.Errorf("localConfigPath() = %s, want %s", got, ymlPath)
+	}
+}
+
+func TestLoadFindsYamlSpelling(t *testing.T) {
+	origWd, _ := os.Getwd()
+	defer os.Chdir(origWd)
+
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+
+	localConfig := []byte("version: 1\nincremental:\n  max_files: 9\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, ".watchman.yaml"), localConfig, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Incremental.MaxFiles != 9 {
+		t.Errorf("MaxFiles = %d, want 9 (.watchman.yaml should be discovered)", cfg.Incremental.MaxFiles)
+	}
+}
+
+func TestNormalizePaths(t *testing.T) {
+	got := normalizePaths([]string{"/tmp/", "/var/log", "src/", "/"})
+	want := []string{"/tmp", "/var/log", "src", "/"}
+	if len(got) != len(want) {
+		t.Fatalf("normalizePaths() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("normalizePaths()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNormalizeGlobs(t *testing.T) {
+	got := normalizeGlobs([]string{"./src/**", "vendor/", "docs/**/*.md", "/"})
+	want := []string{"src/**", "vendor", "docs/**/*.md", "/"}
+	if len(got) != len(want) {
+		t.Fatalf("normalizeGlobs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("normalizeGlobs()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMergeScopeDedupsAcrossFiles(t *testing.T) {
+	cfg := Default()
+	cfg.merge(&Config{Scope: ScopeConfig{
+		Allow: []string{"src/**", "vendor/"},
+		Block: []string{"./gen/**"},
+	}})
+	cfg.merge(&Config{Scope: ScopeConfig{
+		Allow: []string{"./src/**", "vendor", "docs/**"},
+		Block: []string{"gen/**/"},
+	}})
+
+	wantAllow := []string{"src/**", "vendor", "docs/**"}
+	if len(cfg.Scope.Allow) != len(wantAllow) {
+		t.Fatalf("Scope.Allow = %v, want %v", cfg.Scope.Allow, wantAllow)
+	}
+	for i := range wantAllow {
+		if cfg.Scope.Allow[i] != wantAllow[i] {
+			t.Errorf("Scope.Allow[%d] = %q, want %q (base-first order)", i, cfg.Scope.Allow[i], wantAllow[i])
+		}
+	}
+
+	if len(cfg.Scope.Block) != 1 || cfg.Scope.Block[0] != "gen/**" {
+		t.Errorf("Scope.Block = %v, want [gen/**]", cfg.Scope.Block)
+	}
+}
+
+func TestMergeScopeOrderStableAcrossIncludeOrder(t *testing.T) {
+	first := Default()
+	first.merge(&Config{Scope: ScopeConfig{Allow: []string{"src/**", "docs/**"}}})
+	first.merge(&Config{Scope: ScopeConfig{Allow: []string{"./docs/**", "src/**/"}}})
+
+	second := Default()
+	second.merge(&Config{Scope: ScopeConfig{Allow: []string{"./src/**", "docs/**/"}}})
+	second.merge(&Config{Scope: ScopeConfig{Allow: []string{"docs/**", "src/**"}}})
+
+	if len(first.Scope.Allow) != len(second.Scope.Allow) {
+		t.Fatalf("Scope.Allow lengths differ: %v vs %v", first.Scope.Allow, second.Scope.Allow)
+	}
+	for i := range first.Scope.Allow {
+		if first.Scope.Allow[i] != second.Scope.Allow[i] {
+			t.Errorf("Scope.Allow[%d] differs for equal entries: %q vs %q",
+				i, first.Scope.Allow[i], second.Scope.Allow[i])
+		}
+	}
+}
+
+func TestMergeCollapsesTrailingSlashDuplicates(t *testing.T) {
+	cfg := Default()
+	cfg.merge(&Config{Workspace: WorkspaceConfig{Allow: []string{"/tmp/"}}})
+	cfg.merge(&Config{Workspace: WorkspaceConfig{Allow: []string{"/tmp"}}})
+
+	count := 0
+	for _, p := range cfg.Workspace.Allow {
+		if p == "/tmp" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected /tmp and /tmp/ to collapse to one entry, got %v", cfg.Workspace.Allow)
+	}
+}
@@ -8,6 +8,20 @@ import (
 // Match matches a path against a glob pattern.
 // Supports ** for recursive directory matching.
 func Match(path, pattern string) bool {
+	// A trailing slash marks a directory pattern: it matches the directory
+	// itself and everything under it, mirroring the policy package's
+	// protected-path semantics.
+	if strings.HasSuffix(pattern, "/") {
+		dir := filepath.Clean(pattern)
+		cleaned := filepath.Clean(path)
+		return cleaned == dir || strings.HasPrefix(cleaned, dir+string(filepath.Separator))
+	}
+
+	// A leading ./ anchors the pattern to the workspace root: ./*.go matches
+	// only root-level files, while a bare *.go also matches nested files by
+	// basename.
+	anchored := strings.HasPrefix(pattern, "./")
+
 	path = filepath.Clean(path)
 	pattern = filepath.Clean(pattern)
 
@@ -16,58 +30,44 @@ func Match(path, pattern string) bool {
 	}
 
 	matched, _ := filepath.Match(pattern, path)
-	if matched {
-		return true
+	if matched || anchored {
+		return matched
 	}
 
 	matched, _ = filepath.Match(pattern, filepath.Base(path))
 	return matched
 }
 
-// matchDoublestar handles ** glob patterns.
+// matchDoublestar handles ** glob patterns, including patterns with more
+// than one ** occurrence (e.g. src/**/test/**/*.go).
 func matchDoublestar(path, pattern string) bool {
-	parts := strings.Split(pattern, "**")
-	if len(parts) != 2 {
-		return false
-	}
-
-	prefix := strings.TrimSuffix(parts[0], string(filepath.Separator))
-	suffix := strings.TrimPrefix(parts[1], string(filepath.Separator))
-
-	if prefix != "" && !strings.HasPrefix(path, prefix) {
-		return false
-	}
-
-	if suffix == "" {
-		return true
-	}
-
-	remaining := path
-	if prefix != "" {
-		remaining = strings.TrimPrefix(path, prefix)
-		remaining = strings.TrimPrefix(remaining, string(filepath.Separator))
-	}
+	sep := string(filepath.Separator)
+	return matchSegments(strings.Split(path, sep), strings.Split(pattern, sep))
+}
 
-	if suffix == "" {
-		return true
+// matchSegments recursively matches path segments against pattern segments,
+// where a ** segment matches zero or more path segments.
+func matchSegments(path, pattern []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
 	}
 
-	pathParts := strings.Split(remaining, string(filepath.Separator))
-	for i := range pathParts {
-		candidate := strings.Join(pathParts[i:], string(filepath.Separator))
-		matched, _ := filepath.Match(suffix, candidate)
-		if matched {
-			return true
-		}
-		if len(pathParts[i:]) == 1 {
-			matched, _ = filepath.Match(suffix, pathParts[len(pathParts)-1])
-			if matched {
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(path[i:], pattern[1:]) {
 				return true
 			}
 		}
+		return false
 	}
 
-	return false
+	if len(path) == 0 {
+		return false
+	}
+	if matched, _ := filepath.Match(pattern[0], path[0]); !matched {
+		return false
+	}
+	return matchSegments(path[1:], pattern[1:])
 }
 
 // MatchAny returns true if the path matches any of the patterns.
@@ -19,7 +19,20 @@ func TestMatch(t *testing.T) {
 		{"doublestar prefix", "src/pkg/main.go", "src/**/*.go", true},
 		{"doublestar wrong prefix", "vendor/pkg/main.go", "src/**/*.go", false},
 		{"doublestar all", "anything/goes/here", "**", true},
+		{"multi doublestar", "src/a/test/b/c.go", "src/**/test/**/*.go", true},
+		{"multi doublestar no match", "src/a/b.go", "src/**/test/**/*.go", false},
+		{"multi doublestar zero segments", "src/test/c.go", "src/**/test/**/*.go", true},
+		{"leading double doublestar", "a/b/c.go", "**/**/*.go", true},
 		{"relative path", "./src/main.go", "src/*.go", true},
+		{"trailing slash matches under dir", "vendor/lib.go", "vendor/", true},
+		{"trailing slash matches deep path", "vendor/a/b/lib.go", "vendor/", true},
+		{"trailing slash matches dir itself", "vendor", "vendor/", true},
+		{"trailing slash wrong dir", "vendor2/lib.go", "vendor/", false},
+		{"anchored star root file", "main.go", "./*.go", true},
+		{"anchored star nested file", "src/pkg/main.go", "./*.go", false},
+		{"anchored directory pattern", "src/main.go", "./src/*.go", true},
+		{"anchored wrong directory", "pkg/main.go", "./src/*.go", false},
+		{"unanchored still matches by basename", "src/pkg/main.go", "*.go", true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,91 @@
+package glob
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Matcher holds a pattern list in precompiled form. Match re-derives the
+// per-pattern state (directory prefix, anchoring, segment split) on every
+// call; a Matcher does that work once at construction so callers that test
+// many paths against the same patterns avoid the repeated parsing.
+type Matcher struct {
+	patterns []compiledPattern
+}
+
+// compiledPattern is one pattern with its per-pattern state precomputed.
+type compiledPattern struct {
+	// dir is the cleaned directory prefix for trailing-slash patterns;
+	// empty for all other pattern kinds.
+	dir string
+	// segments holds the split pattern for ** patterns; nil otherwise.
+	segments []string
+	// cleaned is the cleaned pattern for plain filepath.Match patterns.
+	cleaned string
+	// anchored marks a leading ./, which disables basename fallback.
+	anchored bool
+}
+
+// Compile precompiles a pattern list for repeated matching. The result
+// matches exactly as MatchAny would against the same patterns.
+func Compile(patterns []string) *Matcher {
+	m := &Matcher{patterns: make([]compiledPattern, 0, len(patterns))}
+	for _, pattern := range patterns {
+		var cp compiledPattern
+		if strings.HasSuffix(pattern, "/") {
+			cp.dir = filepath.Clean(pattern)
+		} else {
+			cp.anchored = strings.HasPrefix(pattern, "./")
+			cp.cleaned = filepath.Clean(pattern)
+			if strings.Contains(cp.cleaned, "**") {
+				cp.segments = strings.Split(cp.cleaned, string(filepath.Separator))
+			}
+		}
+		m.patterns = append(m.patterns, cp)
+	}
+	return m
+}
+
+// Len returns the number of compiled patterns.
+func (m *Matcher) Len() int {
+	return len(m.patterns)
+}
+
+// Match reports whether the path matches any compiled pattern.
+func (m *Matcher) Match(path string) bool {
+	if len(m.patterns) == 0 {
+		return false
+	}
+
+	cleaned := filepath.Clean(path)
+	base := filepath.Base(cleaned)
+	var split []string
+
+	for _, cp := range m.patterns {
+		if cp.dir != "" {
+			if cleaned == cp.dir || strings.HasPrefix(cleaned, cp.dir+string(filepath.Separator)) {
+				return true
+			}
+			continue
+		}
+		if cp.segments != nil {
+			if split == nil {
+				split = strings.Split(cleaned, string(filepath.Separator))
+			}
+			if matchSegments(split, cp.segments) {
+				return true
+			}
+			continue
+		}
+		if matched, _ := filepath.Match(cp.cleaned, cleaned); matched {
+			return true
+		}
+		if cp.anchored {
+			continue
+		}
+		if matched, _ := filepath.Match(cp.cleaned, base); matched {
+			return true
+		}
+	}
+	return false
+}
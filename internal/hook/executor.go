@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"time"
 
 	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/policy"
 )
 
 const defaultTimeout = 5 * time.Second
@@ -19,8 +22,20 @@ type HookInput struct {
 	ToolInput  map[string]interface{} `json:"tool_input"`
 	Paths      []string               `json:"paths"`
 	WorkingDir string                 `json:"working_dir"`
+	// Stage names the point in the evaluation pipeline the hook runs at.
+	// Watchman currently runs external hooks after the built-in rules, so
+	// the value is always "post"; other values are reserved.
+	Stage string `json:"stage"`
+	// TriggerReason carries the warnings emitted by earlier rules in this
+	// evaluation, so a hook can see why it is being consulted.
+	TriggerReason string `json:"trigger_reason,omitempty"`
 }
 
+// Hook pipeline stages reported via HookInput.Stage.
+const (
+	HookStagePost = "post"
+)
+
 // HookOutput is the JSON structure expected from hook stdout.
 type HookOutput struct {
 	Decision string `json:"decision"`
@@ -52,6 +67,14 @@ func (e *HookExecutor) Execute(hookCfg *config.HookConfig, input HookInput) Resu
 
 	cmd := exec.CommandContext(ctx, hookCfg.Command, hookCfg.Args...)
 
+	if hookCfg.Workdir != "" {
+		dir, err := resolveHookWorkdir(hookCfg.Workdir)
+		if err != nil {
+			return e.handleError(hookCfg, err.Error())
+		}
+		cmd.Dir = dir
+	}
+
 	inputJSON, err := json.Marshal(input)
 	if err != nil {
 		return e.handleError(hookCfg, "failed to encode input: "+err.Error())
@@ -92,6 +115,25 @@ func (e *HookExecutor) Execute(hookCfg *config.HookConfig, input HookInput) Resu
 	return Result{Allowed: true}
 }
 
+// resolveHookWorkdir resolves a configured hook workdir against the current
+// working directory and rejects directories outside the workspace.
+func resolveHookWorkdir(workdir string) (string, error) {
+	dir := workdir
+	if !filepath.IsAbs(dir) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", errors.New("cannot resolve workdir: " + err.Error())
+		}
+		dir = filepath.Join(cwd, dir)
+	}
+	dir = filepath.Clean(dir)
+
+	if policy.ViolatesWorkspaceBoundary(dir) {
+		return "", errors.New("hook workdir outside workspace: " + workdir)
+	}
+	return dir, nil
+}
+
 func isCommandNotFound(err error) bool {
 	if exitErr, ok := err.(*exec.ExitError); ok {
 		return exitErr.ExitCode() == 127
@@ -110,7 +152,7 @@ func (e *HookExecutor) outputToResult(output HookOutput) Result {
 	case "deny":
 		return Result{Allowed: false, Reason: output.Reason}
 	case "advise":
-		return Result{Allowed: true, Warning: output.Warning}
+		return Result{Allowed: true, Warnings: []Warning{{Message: output.Warning}}}
 	default:
 		return Result{Allowed: true}
 	}
@@ -120,5 +162,5 @@ func (e *HookExecutor) handleError(hookCfg *config.HookConfig, errMsg string) Re
 	if hookCfg.OnError == "deny" {
 		return Result{Allowed: false, Reason: "hook error: " + errMsg}
 	}
-	return Result{Allowed: true, Warning: "hook error (allowed): " + errMsg}
+	return Result{Allowed: true, Warnings: []Warning{{Source: hookCfg.Name, Message: "hook error (allowed): " + errMsg}}}
 }
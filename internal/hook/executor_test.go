@@ -1,8 +1,10 @@
 package hook
 
 import (
+	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -53,6 +55,80 @@ func TestHookExecutorExecuteDeny(t *testing.T) {
 	}
 }
 
+func TestHookExecutorWorkdir(t *testing.T) {
+	e := NewHookExecutor()
+	hookCfg := &config.HookConfig{
+		Name:    "test-workdir",
+		Command: testdataPath("pwd.sh"),
+		Workdir: "testdata",
+	}
+
+	result := e.Execute(hookCfg, HookInput{})
+	if !result.Allowed {
+		t.Fatalf("Execute() allowed = false, want true")
+	}
+
+	want := "cwd: " + filepath.Clean(testdataPath(""))
+	if result.Warning() != want {
+		t.Errorf("Execute() warning = %q, want %q", result.Warning(), want)
+	}
+}
+
+func TestHookExecutorWorkdirDefault(t *testing.T) {
+	e := NewHookExecutor()
+	hookCfg := &config.HookConfig{
+		Name:    "test-workdir-default",
+		Command: testdataPath("pwd.sh"),
+	}
+
+	result := e.Execute(hookCfg, HookInput{})
+	if !result.Allowed {
+		t.Fatalf("Execute() allowed = false, want true")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Warning() != "cwd: "+cwd {
+		t.Errorf("Execute() warning = %q, want cwd %q", result.Warning(), cwd)
+	}
+}
+
+func TestHookExecutorWorkdirOutsideWorkspace(t *testing.T) {
+	e := NewHookExecutor()
+	hookCfg := &config.HookConfig{
+		Name:    "test-workdir-escape",
+		Command: testdataPath("pwd.sh"),
+		Workdir: "/etc",
+	}
+
+	// Default on_error allows with a warning; the hook must not run.
+	result := e.Execute(hookCfg, HookInput{})
+	if !result.Allowed {
+		t.Fatalf("Execute() allowed = false, want true")
+	}
+	if !strings.Contains(result.Warning(), "outside workspace") {
+		t.Errorf("Execute() warning = %q, want outside-workspace error", result.Warning())
+	}
+}
+
+func TestHookExecutorStage(t *testing.T) {
+	e := NewHookExecutor()
+	hookCfg := &config.HookConfig{
+		Name:    "test-stage",
+		Command: testdataPath("stage.sh"),
+	}
+
+	result := e.Execute(hookCfg, HookInput{Stage: HookStagePost})
+	if !result.Allowed {
+		t.Fatalf("Execute() allowed = false, want true")
+	}
+	if result.Warning() != "stage: post" {
+		t.Errorf("Execute() warning = %q, want %q", result.Warning(), "stage: post")
+	}
+}
+
 func TestHookExecutorExecuteAdvise(t *testing.T) {
 	e := NewHookExecutor()
 	hookCfg := &config.HookConfig{
@@ -64,8 +140,8 @@ func TestHookExecutorExecuteAdvise(t *testing.T) {
 	if !result.Allowed {
 		t.Errorf("Execute() allowed = false, want true")
 	}
-	if result.Warning != "consider this" {
-		t.Errorf("Execute() warning = %q, want %q", result.Warning, "consider this")
+	if result.Warning() != "consider this" {
+		t.Errorf("Execute() warning = %q, want %q", result.Warning(), "consider this")
 	}
 }
 
@@ -97,7 +173,7 @@ func TestHookExecutorExecuteTimeout(t *testing.T) {
 	if !result.Allowed {
 		t.Errorf("Execute() allowed = false, want true (on_error default is allow)")
 	}
-	if result.Warning == "" {
+	if result.Warning() == "" {
 		t.Error("Execute() should have warning about timeout")
 	}
 }
@@ -192,8 +268,8 @@ func TestHookExecutorOutputToResult(t *testing.T) {
 			if got.Reason != tt.wantReason {
 				t.Errorf("outputToResult() reason = %q, want %q", got.Reason, tt.wantReason)
 			}
-			if got.Warning != tt.wantWarning {
-				t.Errorf("outputToResult() warning = %q, want %q", got.Warning, tt.wantWarning)
+			if got.Warning() != tt.wantWarning {
+				t.Errorf("outputToResult() warning = %q, want %q", got.Warning(), tt.wantWarning)
 			}
 		})
 	}
@@ -14,7 +14,8 @@ func ExtractPaths(toolName string, toolInput map[string]interface{}) []string {
 	case "Grep":
 		return extractGrepPaths(toolInput)
 	}
-	return nil
+	// Custom tools classified via config are expected to carry a file_path.
+	return extractFilePath(toolInput)
 }
 
 func extractBashPaths(toolInput map[string]interface{}) []string {
@@ -24,23 +25,53 @@ func extractBashPaths(toolInput map[string]interface{}) []string {
 	}
 	cmd := parser.Parse(cmdStr)
 	var paths []string
-	paths = append(paths, cmd.Args...)
-	for _, v := range cmd.Flags {
-		if v != "" {
-			paths = append(paths, v)
+	seen := make(map[string]bool)
+
+	add := func(v string) {
+		if v == "" || seen[v] {
+			return
 		}
+		seen[v] = true
+		paths = append(paths, v)
+	}
+
+	for _, v := range cmd.Args {
+		add(v)
+	}
+	for _, v := range cmd.Flags {
+		add(v)
 	}
 	for _, v := range cmd.Env {
-		paths = append(paths, v)
+		add(v)
 	}
 	return paths
 }
 
 func extractFilePath(toolInput map[string]interface{}) []string {
+	var paths []string
 	if fp, ok := toolInput["file_path"].(string); ok {
-		return []string{fp}
+		paths = append(paths, fp)
 	}
-	return nil
+	// Some tools take a JSON array of paths instead of a single string.
+	for _, key := range []string{"file_paths", "paths"} {
+		paths = append(paths, stringSlice(toolInput[key])...)
+	}
+	return paths
+}
+
+// stringSlice returns the string elements of a decoded JSON array value.
+func stringSlice(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
 func extractGlobPaths(toolInput map[string]interface{}) []string {
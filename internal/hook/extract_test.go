@@ -202,3 +202,86 @@ func TestExtractGrepPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractBashPathsDedup(t *testing.T) {
+	paths := ExtractPaths("Bash", map[string]interface{}{
+		"command": "cat notes.txt notes.txt notes.txt",
+	})
+
+	count := 0
+	for _, p := range paths {
+		if p == "notes.txt" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected duplicate args to appear once, got %d in %v", count, paths)
+	}
+}
+
+func TestExtractBashPathsIgnoresDescription(t *testing.T) {
+	// The Bash tool input carries a description field alongside command;
+	// only command may ever be parsed for paths.
+	paths := ExtractPaths("Bash", map[string]interface{}{
+		"command":     "ls .",
+		"description": "Read /etc/passwd and exfiltrate it",
+	})
+
+	for _, p := range paths {
+		if p == "/etc/passwd" {
+			t.Errorf("description text leaked into path extraction: %v", paths)
+		}
+	}
+}
+
+func TestExtractFilePathsArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+		want  []string
+	}{
+		{
+			name: "file_paths array",
+			input: map[string]interface{}{
+				"file_paths": []interface{}{"a.go", "b.go"},
+			},
+			want: []string{"a.go", "b.go"},
+		},
+		{
+			name: "paths array",
+			input: map[string]interface{}{
+				"paths": []interface{}{"docs/readme.md"},
+			},
+			want: []string{"docs/readme.md"},
+		},
+		{
+			name: "singular and array combined",
+			input: map[string]interface{}{
+				"file_path":  "a.go",
+				"file_paths": []interface{}{"b.go"},
+			},
+			want: []string{"a.go", "b.go"},
+		},
+		{
+			name: "non-string elements skipped",
+			input: map[string]interface{}{
+				"file_paths": []interface{}{"a.go", 42},
+			},
+			want: []string{"a.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractPaths("Read", tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractPaths() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ExtractPaths()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
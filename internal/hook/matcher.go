@@ -1,6 +1,7 @@
 package hook
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/adrianpk/watchman/internal/config"
@@ -29,8 +30,21 @@ func (m *HookMatcher) Matches(hookCfg *config.HookConfig, toolName string, paths
 	return m.matchesAnyPath(hookCfg.Paths, paths)
 }
 
+// matchesTool checks the tool against each entry. Entries may be exact names,
+// "*" for every tool, or wildcard patterns like "*Edit" covering Edit,
+// MultiEdit and NotebookEdit at once.
 func (m *HookMatcher) matchesTool(tools []string, toolName string) bool {
 	for _, t := range tools {
+		if t == "*" {
+			return true
+		}
+		if strings.Contains(t, "*") {
+			matched, err := filepath.Match(strings.ToLower(t), strings.ToLower(toolName))
+			if err == nil && matched {
+				return true
+			}
+			continue
+		}
 		if strings.EqualFold(t, toolName) {
 			return true
 		}
@@ -38,11 +52,26 @@ func (m *HookMatcher) matchesTool(tools []string, toolName string) bool {
 	return false
 }
 
+// matchesAnyPath checks each path against the pattern list. Entries may be
+// negated with a ! prefix (e.g. "!**/*_test.go") to carve exclusions out of
+// earlier matches; the last matching entry wins.
 func (m *HookMatcher) matchesAnyPath(patterns []string, paths []string) bool {
 	for _, path := range paths {
-		if glob.MatchAny(path, patterns) {
+		if matchWithNegation(path, patterns) {
 			return true
 		}
 	}
 	return false
 }
+
+func matchWithNegation(path string, patterns []string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		if glob.Match(path, pattern) {
+			matched = !negated
+		}
+	}
+	return matched
+}
@@ -29,6 +29,12 @@ func TestHookMatcherMatchesTool(t *testing.T) {
 		{"multiple tools second", []string{"Read", "Write"}, "Write", true},
 		{"no match", []string{"Read"}, "Write", false},
 		{"empty tools", []string{}, "Write", false},
+		{"star matches all", []string{"*"}, "Read", true},
+		{"suffix wildcard edit", []string{"*Edit"}, "Edit", true},
+		{"suffix wildcard multiedit", []string{"*Edit"}, "MultiEdit", true},
+		{"suffix wildcard notebookedit", []string{"*Edit"}, "NotebookEdit", true},
+		{"suffix wildcard excludes read", []string{"*Edit"}, "Read", false},
+		{"wildcard case insensitive", []string{"*edit"}, "MultiEdit", true},
 	}
 
 	for _, tt := range tests {
@@ -134,3 +140,42 @@ func TestHookMatcherMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestHookMatcherPathNegation(t *testing.T) {
+	m := NewHookMatcher()
+	patterns := []string{"**/*.go", "!**/*_test.go"}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"source file matches", "main.go", true},
+		{"nested source matches", "internal/hook/eval.go", true},
+		{"test file excluded", "main_test.go", false},
+		{"nested test excluded", "internal/hook/eval_test.go", false},
+		{"non-go never matches", "style.css", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.matchesAnyPath(patterns, []string{tt.path})
+			if got != tt.want {
+				t.Errorf("matchesAnyPath(%v, %q) = %v, want %v", patterns, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookMatcherNegationLastMatchWins(t *testing.T) {
+	m := NewHookMatcher()
+
+	// A later positive pattern re-includes what an earlier negation excluded.
+	patterns := []string{"**/*.go", "!**/*_test.go", "cmd/**/*_test.go"}
+	if !m.matchesAnyPath(patterns, []string{"cmd/watchman/main_test.go"}) {
+		t.Error("later positive pattern should re-include the path")
+	}
+	if m.matchesAnyPath(patterns, []string{"internal/hook/eval_test.go"}) {
+		t.Error("negation should still exclude paths outside the re-include")
+	}
+}
@@ -0,0 +1,4 @@
+#!/bin/bash
+input=$(cat)
+stage=$(echo "$input" | sed -n 's/.*"stage":"\([^"]*\)".*/\1/p')
+echo "{\"decision\":\"advise\",\"warning\":\"stage: $stage\"}"
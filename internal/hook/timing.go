@@ -0,0 +1,52 @@
+package hook
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// timingEnvVar enables per-stage timing output when set to "1".
+const timingEnvVar = "WATCHMAN_TIMING"
+
+// stageTimer records per-stage durations of an evaluation when enabled.
+type stageTimer struct {
+	enabled bool
+	out     io.Writer
+	stages  []stageTiming
+}
+
+type stageTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// newStageTimer creates a timer that reports to stderr when WATCHMAN_TIMING=1.
+func newStageTimer() *stageTimer {
+	return &stageTimer{
+		enabled: os.Getenv(timingEnvVar) == "1",
+		out:     os.Stderr,
+	}
+}
+
+// start begins timing a stage and returns a function that records its duration.
+func (t *stageTimer) start(name string) func() {
+	if !t.enabled {
+		return func() {}
+	}
+	begin := time.Now()
+	return func() {
+		t.stages = append(t.stages, stageTiming{name: name, duration: time.Since(begin)})
+	}
+}
+
+// report prints the recorded stage durations. No output when disabled.
+func (t *stageTimer) report() {
+	if !t.enabled || len(t.stages) == 0 {
+		return
+	}
+	for _, s := range t.stages {
+		fmt.Fprintf(t.out, "timing: %s %s\n", s.name, s.duration)
+	}
+}
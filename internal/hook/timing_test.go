@@ -0,0 +1,45 @@
+package hook
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStageTimerDisabledByDefault(t *testing.T) {
+	t.Setenv(timingEnvVar, "")
+
+	timer := newStageTimer()
+	var buf bytes.Buffer
+	timer.out = &buf
+
+	stop := timer.start("workspace")
+	stop()
+	timer.report()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no timing output when %s is unset, got: %s", timingEnvVar, buf.String())
+	}
+}
+
+func TestStageTimerEnabled(t *testing.T) {
+	t.Setenv(timingEnvVar, "1")
+
+	timer := newStageTimer()
+	var buf bytes.Buffer
+	timer.out = &buf
+
+	stop := timer.start("workspace")
+	stop()
+	stop = timer.start("scope")
+	stop()
+	timer.report()
+
+	output := buf.String()
+	if !strings.Contains(output, "timing: workspace") {
+		t.Errorf("expected workspace timing in output, got: %s", output)
+	}
+	if !strings.Contains(output, "timing: scope") {
+		t.Errorf("expected scope timing in output, got: %s", output)
+	}
+}
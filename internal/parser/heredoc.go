@@ -1,6 +1,9 @@
 package parser
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
 
 // heredocPattern matches heredoc start: << or <<- followed by delimiter
 var heredocPattern = regexp.MustCompile(`<<-?\s*['"]?(\w+)['"]?`)
@@ -34,3 +37,58 @@ func stripHeredocs(cmd string) string {
 
 	return result
 }
+
+// HeredocWrite represents heredoc content redirected into a file,
+// e.g. `cat > src/x.go <<EOF ... EOF`.
+type HeredocWrite struct {
+	Target string
+	Body   string
+}
+
+// redirectTargetPattern matches an output redirect (> or >>) and its target.
+var redirectTargetPattern = regexp.MustCompile(`>>?\s*([^\s<>|&;]+)`)
+
+// RedirectTargets returns the targets of every output redirect (> or >>)
+// in a command, with heredoc bodies stripped first so their content is not
+// mistaken for redirects.
+func RedirectTargets(cmd string) []string {
+	var targets []string
+	for _, m := range redirectTargetPattern.FindAllStringSubmatch(stripHeredocs(cmd), -1) {
+		targets = append(targets, m[1])
+	}
+	return targets
+}
+
+// ExtractHeredocWrite extracts the redirect target and heredoc body from a
+// command. Returns false when the command has no heredoc feeding a redirect
+// target, so content-based rules know there is nothing to inspect.
+func ExtractHeredocWrite(cmd string) (HeredocWrite, bool) {
+	m := heredocPattern.FindStringSubmatchIndex(cmd)
+	if m == nil {
+		return HeredocWrite{}, false
+	}
+	delimiter := cmd[m[2]:m[3]]
+
+	rest := cmd[m[1]:]
+	nl := strings.Index(rest, "\n")
+	if nl == -1 {
+		return HeredocWrite{}, false
+	}
+	bodyStart := nl + 1
+
+	closingPattern := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(delimiter) + `\s*$`)
+	loc := closingPattern.FindStringIndex(rest[bodyStart:])
+	if loc == nil {
+		return HeredocWrite{}, false
+	}
+	body := rest[bodyStart : bodyStart+loc[0]]
+
+	// The redirect target may appear before or after the heredoc marker;
+	// strip the body first so its content is not mistaken for a redirect.
+	target := redirectTargetPattern.FindStringSubmatch(stripHeredocs(cmd))
+	if target == nil {
+		return HeredocWrite{}, false
+	}
+
+	return HeredocWrite{Target: target[1], Body: body}, true
+}
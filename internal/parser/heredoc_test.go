@@ -122,3 +122,63 @@ EOF`
 		t.Errorf("Args should contain /tmp/real-file.txt, got %v", parsed.Args)
 	}
 }
+
+func TestExtractHeredocWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        string
+		wantTarget string
+		wantBody   string
+		wantOK     bool
+	}{
+		{
+			name:       "redirect before heredoc",
+			cmd:        "cat > src/x.go <<EOF\npackage main\nEOF",
+			wantTarget: "src/x.go",
+			wantBody:   "package main\n",
+			wantOK:     true,
+		},
+		{
+			name:       "quoted delimiter",
+			cmd:        "cat > out.txt <<'END'\nhello\nEND",
+			wantTarget: "out.txt",
+			wantBody:   "hello\n",
+			wantOK:     true,
+		},
+		{
+			name:       "append redirect",
+			cmd:        "cat >> log.txt <<EOF\nline\nEOF",
+			wantTarget: "log.txt",
+			wantBody:   "line\n",
+			wantOK:     true,
+		},
+		{
+			name:   "no heredoc",
+			cmd:    "echo hello > out.txt",
+			wantOK: false,
+		},
+		{
+			name:   "heredoc without redirect",
+			cmd:    "cat <<EOF\nhello\nEOF",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hw, ok := ExtractHeredocWrite(tt.cmd)
+			if ok != tt.wantOK {
+				t.Fatalf("ExtractHeredocWrite(%q) ok = %v, want %v", tt.cmd, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if hw.Target != tt.wantTarget {
+				t.Errorf("Target = %q, want %q", hw.Target, tt.wantTarget)
+			}
+			if hw.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", hw.Body, tt.wantBody)
+			}
+		})
+	}
+}
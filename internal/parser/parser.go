@@ -140,6 +140,12 @@ func tokenize(cmd string) []string {
 			continue
 		}
 
+		// An unquoted # starting a new token begins a comment; drop the rest.
+		// A # inside a token (file#1) or inside quotes is literal.
+		if r == '#' && !inSingleQuote && !inDoubleQuote && current.Len() == 0 {
+			return tokens
+		}
+
 		switch r {
 		case '\\':
 			if inSingleQuote {
@@ -194,3 +200,65 @@ func hasSubcommand(program string) bool {
 	}
 	return programs[program]
 }
+
+// SplitSegments splits a shell command on |, &&, || and ; separators,
+// respecting quoted strings, so each pipeline stage or chained command can
+// be inspected on its own.
+func SplitSegments(cmd string) []string {
+	var segments []string
+	var current strings.Builder
+	i := 0
+
+	for i < len(cmd) {
+		ch := cmd[i]
+
+		switch ch {
+		case '|':
+			segments = append(segments, current.String())
+			current.Reset()
+			// Skip || (treat as single separator)
+			if i+1 < len(cmd) && cmd[i+1] == '|' {
+				i++
+			}
+		case '&':
+			if i+1 < len(cmd) && cmd[i+1] == '&' {
+				segments = append(segments, current.String())
+				current.Reset()
+				i++ // Skip second &
+			} else {
+				// Background &, still part of current segment
+				current.WriteByte(ch)
+			}
+		case ';':
+			segments = append(segments, current.String())
+			current.Reset()
+		case '\'', '"':
+			// Skip quoted strings entirely
+			quote := ch
+			current.WriteByte(ch)
+			i++
+			for i < len(cmd) && cmd[i] != quote {
+				if cmd[i] == '\\' && i+1 < len(cmd) {
+					current.WriteByte(cmd[i])
+					i++
+				}
+				if i < len(cmd) {
+					current.WriteByte(cmd[i])
+					i++
+				}
+			}
+			if i < len(cmd) {
+				current.WriteByte(cmd[i])
+			}
+		default:
+			current.WriteByte(ch)
+		}
+		i++
+	}
+
+	if current.Len() > 0 {
+		segments = append(segments, current.String())
+	}
+
+	return segments
+}
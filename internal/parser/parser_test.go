@@ -91,6 +91,21 @@ func TestParse(t *testing.T) {
 			cmd:  "go test -short ./...",
 			want: Command{Raw: "go test -short ./...", Env: map[string]string{}, Program: "go", Subcommand: "test", Args: []string{"./..."}, Flags: map[string]string{"-short": ""}},
 		},
+		{
+			name: "trailing comment dropped",
+			cmd:  "rm x # ../../etc",
+			want: Command{Raw: "rm x # ../../etc", Env: map[string]string{}, Program: "rm", Args: []string{"x"}, Flags: map[string]string{}},
+		},
+		{
+			name: "hash inside filename survives",
+			cmd:  "cat file#1",
+			want: Command{Raw: "cat file#1", Env: map[string]string{}, Program: "cat", Args: []string{"file#1"}, Flags: map[string]string{}},
+		},
+		{
+			name: "hash inside quotes survives",
+			cmd:  `echo "a # b"`,
+			want: Command{Raw: `echo "a # b"`, Env: map[string]string{}, Program: "echo", Args: []string{"a # b"}, Flags: map[string]string{}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,34 @@
+package policy
+
+import (
+	"io"
+	"os"
+)
+
+// maxRuleReadBytes caps how much of a target file content-based rules read.
+// Declared as a variable so tests can lower it.
+var maxRuleReadBytes int64 = 1 << 20 // 1 MiB
+
+// ReadFileCapped reads at most maxRuleReadBytes from a regular file, for
+// content-based rules that inspect targets on disk. It reports ok=false for
+// missing, unreadable or non-regular files (fifos, devices), so callers fail
+// open instead of hanging on a special file or exhausting memory on a huge
+// one.
+func ReadFileCapped(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxRuleReadBytes))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
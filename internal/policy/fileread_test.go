@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestReadFileCappedTruncatesLargeFile(t *testing.T) {
+	origMax := maxRuleReadBytes
+	defer func() { maxRuleReadBytes = origMax }()
+	maxRuleReadBytes = 16
+
+	path := filepath.Join(t.TempDir(), "large.txt")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("a"), 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, ok := ReadFileCapped(path)
+	if !ok {
+		t.Fatal("regular file should be readable")
+	}
+	if len(data) != 16 {
+		t.Errorf("read %d bytes, want cap of 16", len(data))
+	}
+}
+
+func TestReadFileCappedSkipsFifo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipe")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Skipf("cannot create fifo: %v", err)
+	}
+
+	// A fifo must be skipped without opening it, or the read would block
+	// forever waiting for a writer.
+	if _, ok := ReadFileCapped(path); ok {
+		t.Error("fifo should not be readable")
+	}
+}
+
+func TestReadFileCappedMissingFile(t *testing.T) {
+	if _, ok := ReadFileCapped(filepath.Join(t.TempDir(), "absent")); ok {
+		t.Error("missing file should report ok=false")
+	}
+}
@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// gitOutput runs git with the given arguments and returns its stdout.
+// Declared as a variable so tests can inject canned output.
+var gitOutput = func(args ...string) ([]byte, error) {
+	return exec.Command("git", args...).Output()
+}
+
+// ModifiedFileCount returns the number of modified files reported by
+// git status --porcelain, or -1 when git is unavailable.
+func ModifiedFileCount() int {
+	return countGitModifiedFiles()
+}
+
+// TrackedFiles returns the paths present in the git index, via
+// git ls-files. When git is unavailable it returns nil so callers fail open.
+func TrackedFiles() []string {
+	output, err := gitOutput("ls-files")
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// StagedFiles returns the paths currently staged for commit, via
+// git diff --cached --name-only. When git is unavailable it returns nil so
+// callers fail open (mirroring the incremental rule's behavior).
+func StagedFiles() []string {
+	output, err := gitOutput("diff", "--cached", "--name-only")
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// ChangedFiles returns the working-tree paths git status --porcelain
+// reports, i.e. what a mass git add (-A, .) would stage. When git is
+// unavailable it returns nil so callers fail open.
+func ChangedFiles() []string {
+	output, err := gitOutput("status", "--porcelain")
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		// Renames are reported as "old -> new"; the destination is staged.
+		if idx := strings.Index(path, " -> "); idx != -1 {
+			path = path[idx+len(" -> "):]
+		}
+		files = append(files, path)
+	}
+	return files
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStagedFiles(t *testing.T) {
+	orig := gitOutput
+	defer func() { gitOutput = orig }()
+
+	gitOutput = func(args ...string) ([]byte, error) {
+		return []byte("src/main.go\ninternal/policy/git.go\n"), nil
+	}
+
+	got := StagedFiles()
+	want := []string{"src/main.go", "internal/policy/git.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StagedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestStagedFilesEmpty(t *testing.T) {
+	orig := gitOutput
+	defer func() { gitOutput = orig }()
+
+	gitOutput = func(args ...string) ([]byte, error) {
+		return []byte("\n"), nil
+	}
+
+	if got := StagedFiles(); got != nil {
+		t.Errorf("StagedFiles() = %v, want nil", got)
+	}
+}
+
+func TestStagedFilesGitUnavailable(t *testing.T) {
+	orig := gitOutput
+	defer func() { gitOutput = orig }()
+
+	gitOutput = func(args ...string) ([]byte, error) {
+		return nil, errors.New("git not found")
+	}
+
+	if got := StagedFiles(); got != nil {
+		t.Errorf("StagedFiles() = %v, want nil on git failure", got)
+	}
+}
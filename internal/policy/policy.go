@@ -4,8 +4,11 @@ package policy
 import "github.com/adrianpk/watchman/internal/parser"
 
 // Decision represents the result of evaluating a command against rules.
+// Ask marks a disallowed decision that should prompt the user for
+// confirmation instead of failing outright.
 type Decision struct {
 	Allowed bool
+	Ask     bool
 	Reason  string
 	Warning string
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/adrianpk/watchman/internal/config"
 )
 
 // alwaysProtected contains paths that are NEVER accessible, regardless of config.
@@ -30,23 +32,92 @@ var protectedFilenames = []string{
 	".watchman.yml",
 }
 
-// IsAlwaysProtected checks if a path matches any hardcoded protected path.
-// This check cannot be overridden by configuration.
-func IsAlwaysProtected(p string) bool {
+// getwd resolves the current working directory. Declared as a variable so
+// tests can simulate a getwd failure.
+var getwd = os.Getwd
+
+// getwdFailOpen selects what happens when the working directory cannot be
+// determined: false (the default) treats unresolvable relative paths as
+// violations, true lets them through. Configured via workspace.on_getwd_error.
+var getwdFailOpen = false
+
+// SetGetwdFailOpen switches the policy for getwd failures across protection
+// and workspace checks.
+func SetGetwdFailOpen(enabled bool) {
+	getwdFailOpen = enabled
+}
+
+// filenameProtection gates the protectedFilenames check. It defaults to on
+// and is only disabled via protect.config_filename: false, for users who
+// keep fixture files named .watchman.yml in their test suites.
+var filenameProtection = true
+
+// SetFilenameProtection enables or disables the filename-based protection
+// check. Path-based protection is unaffected.
+func SetFilenameProtection(enabled bool) {
+	filenameProtection = enabled
+}
+
+// Protector checks paths against the always-protected set. The hardcoded
+// defaults are baked in at construction; config can only extend them, never
+// remove them (except the narrow filename toggle).
+type Protector struct {
+	paths     []string
+	filenames []string
+}
+
+// NewProtector builds a Protector from config. protect.extra patterns are
+// appended to the defaults, and protect.config_filename: false drops the
+// filename-based rule.
+func NewProtector(cfg *config.ProtectConfig) *Protector {
+	p := &Protector{
+		paths:     alwaysProtected,
+		filenames: protectedFilenames,
+	}
+	if cfg == nil {
+		return p
+	}
+	if len(cfg.Extra) > 0 {
+		p.paths = append(append([]string{}, alwaysProtected...), cfg.Extra...)
+	}
+	if cfg.ConfigFilename != nil && !*cfg.ConfigFilename {
+		p.filenames = nil
+	}
+	return p
+}
+
+// IsProtected checks if a path matches the protected set. A nil receiver
+// falls back to the hardcoded defaults.
+func (pr *Protector) IsProtected(p string) bool {
 	if p == "" {
 		return false
 	}
 
-	absPath := resolvePath(p)
+	paths := alwaysProtected
+	filenames := protectedFilenames
+	if !filenameProtection {
+		filenames = nil
+	}
+	if pr != nil {
+		paths = pr.paths
+		filenames = pr.filenames
+	}
+
+	absPath, ok := resolvePath(p)
+	if !ok {
+		// Cannot resolve a relative path without a working directory;
+		// apply the configured getwd-failure policy.
+		return !getwdFailOpen
+	}
 
 	filename := filepath.Base(absPath)
-	for _, protected := range protectedFilenames {
+	for _, protected := range filenames {
 		if filename == protected {
 			return true
 		}
 	}
 
-	for _, pattern := range alwaysProtected {
+	for _, pattern := range paths {
 		isDir := strings.HasSuffix(pattern, "/")
 
 		expandedPattern := strings.TrimSuffix(pattern, "/")
@@ -68,25 +139,55 @@ func IsAlwaysProtected(p string) bool {
 	return false
 }
 
-// resolvePath converts a path to absolute form.
-func resolvePath(p string) string {
+// IsAlwaysProtected checks if a path matches any hardcoded protected path.
+// This check cannot be overridden by configuration.
+func IsAlwaysProtected(p string) bool {
+	var pr *Protector
+	return pr.IsProtected(p)
+}
+
+// expandHome expands a leading "~/", "$HOME/" or "${HOME}/" in a pattern to
+// the user's home directory, so configs authored with any of the spellings
+// behave identically. Other patterns are returned unchanged.
+func expandHome(pattern string) string {
+	var rest string
+	switch {
+	case strings.HasPrefix(pattern, "~/"):
+		rest = pattern[len("~/"):]
+	case strings.HasPrefix(pattern, "${HOME}/"):
+		rest = pattern[len("${HOME}/"):]
+	case strings.HasPrefix(pattern, "$HOME/"):
+		rest = pattern[len("$HOME/"):]
+	default:
+		return pattern
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return pattern
+	}
+	return filepath.Join(home, rest)
+}
+
+// resolvePath converts a path to absolute form. It shares absFromCwd with
+// the workspace rule so both checks resolve relative traversal identically.
+// ok=false reports a relative path that could not be resolved because the
+// working directory is unknown.
+func resolvePath(p string) (string, bool) {
 	if filepath.IsAbs(p) {
-		return filepath.Clean(p)
+		return filepath.Clean(p), true
 	}
-	if cwd, err := os.Getwd(); err == nil {
-		return filepath.Clean(filepath.Join(cwd, p))
+	cwd, err := getwd()
+	if err != nil {
+		return filepath.Clean(p), false
 	}
-	return filepath.Clean(p)
+	return absFromCwd(p, cwd), true
 }
 
 // matchPath checks if a path matches a pattern.
 // Supports exact match and prefix match (pattern ending with /).
 func matchPath(path, pattern string) bool {
-	if strings.HasPrefix(pattern, "~/") {
-		if home, err := os.UserHomeDir(); err == nil {
-			pattern = filepath.Join(home, pattern[2:])
-		}
-	}
+	pattern = expandHome(pattern)
 
 	if path == pattern {
 		return true
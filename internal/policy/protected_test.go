@@ -1,11 +1,78 @@
 package policy
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/parser"
 )
 
+// chdirProtectedHome sets up a fake home with a nested working directory so
+// relative traversal can be resolved against known paths.
+func chdirProtectedHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	deep := filepath.Join(home, "projects", "app")
+	if err := os.MkdirAll(deep, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	origWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(origWd) })
+	if err := os.Chdir(deep); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+func TestIsAlwaysProtectedRelativeTraversal(t *testing.T) {
+	chdirProtectedHome(t)
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"traversal into ssh", "../../.ssh/id_rsa", true},
+		{"dot-prefixed traversal into ssh", "./../../.ssh/id_rsa", true},
+		{"redundant segments into ssh", "../x/../../.ssh/id_rsa", true},
+		{"traversal into aws", "../../.aws/credentials", true},
+		{"traversal staying in project", "../app/main.go", false},
+		{"plain relative path", "src/main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsAlwaysProtected(tt.path)
+			if got != tt.want {
+				t.Errorf("IsAlwaysProtected(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkspaceDeniesRelativeTraversalIntoProtected(t *testing.T) {
+	chdirProtectedHome(t)
+
+	rule := &ConfineToWorkspace{}
+	cmd := parser.Parse("cat ./../../.ssh/id_rsa")
+
+	decision := rule.Evaluate(cmd)
+	if decision.Allowed {
+		t.Fatal("relative traversal into ~/.ssh should be denied")
+	}
+	if !strings.Contains(decision.Reason, "protected path") {
+		t.Errorf("reason = %q, want protected path reason", decision.Reason)
+	}
+}
+
 func TestIsAlwaysProtected(t *testing.T) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -176,7 +243,10 @@ func TestResolvePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := resolvePath(tt.path)
+			got, ok := resolvePath(tt.path)
+			if !ok {
+				t.Fatalf("resolvePath(%q) could not resolve", tt.path)
+			}
 			if got != tt.want {
 				t.Errorf("resolvePath(%q) = %q, want %q", tt.path, got, tt.want)
 			}
@@ -184,6 +254,51 @@ func TestResolvePath(t *testing.T) {
 	}
 }
 
+func TestGetwdFailurePolicy(t *testing.T) {
+	origGetwd := getwd
+	origFailOpen := getwdFailOpen
+	defer func() {
+		getwd = origGetwd
+		getwdFailOpen = origFailOpen
+	}()
+	getwd = func() (string, error) {
+		return "", errors.New("getwd failed")
+	}
+
+	rule := &ConfineToWorkspace{}
+
+	t.Run("fail closed by default", func(t *testing.T) {
+		SetGetwdFailOpen(false)
+		if !rule.violatesBoundary("src/main.go") {
+			t.Error("unresolvable relative path should violate the boundary when failing closed")
+		}
+		if !IsAlwaysProtected("some/relative/path") {
+			t.Error("unresolvable relative path should be treated as protected when failing closed")
+		}
+	})
+
+	t.Run("fail open when configured", func(t *testing.T) {
+		SetGetwdFailOpen(true)
+		if rule.violatesBoundary("src/main.go") {
+			t.Error("unresolvable relative path should pass when failing open")
+		}
+		if IsAlwaysProtected("some/relative/path") {
+			t.Error("unresolvable relative path should not be protected when failing open")
+		}
+	})
+
+	t.Run("absolute paths unaffected", func(t *testing.T) {
+		SetGetwdFailOpen(false)
+		home, err := os.UserHomeDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsAlwaysProtected(filepath.Join(home, ".ssh", "id_rsa")) {
+			t.Error("absolute protected path should not depend on getwd")
+		}
+	})
+}
+
 func TestMatchPath(t *testing.T) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -244,6 +359,30 @@ func TestMatchPath(t *testing.T) {
 			pattern: "~/.netrc",
 			want:    true,
 		},
+		{
+			name:    "HOME expansion",
+			path:    filepath.Join(home, ".ssh", "id_rsa"),
+			pattern: "$HOME/.ssh/",
+			want:    true,
+		},
+		{
+			name:    "braced HOME expansion",
+			path:    filepath.Join(home, ".ssh", "id_rsa"),
+			pattern: "${HOME}/.ssh/",
+			want:    true,
+		},
+		{
+			name:    "HOME expansion exact",
+			path:    filepath.Join(home, ".netrc"),
+			pattern: "$HOME/.netrc",
+			want:    true,
+		},
+		{
+			name:    "HOME only at start",
+			path:    "/srv/$HOME/.ssh",
+			pattern: "/srv/$HOME/.ssh",
+			want:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -255,3 +394,99 @@ func TestMatchPath(t *testing.T) {
 		})
 	}
 }
+
+func TestSetFilenameProtection(t *testing.T) {
+	defer SetFilenameProtection(true)
+
+	if !IsAlwaysProtected("fixtures/.watchman.yml") {
+		t.Error("filename protection should be enabled by default")
+	}
+
+	SetFilenameProtection(false)
+	if IsAlwaysProtected("fixtures/.watchman.yml") {
+		t.Error("filename protection should be disabled after SetFilenameProtection(false)")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsAlwaysProtected(filepath.Join(home, ".ssh", "id_rsa")) {
+		t.Error("path-based protection must remain active when filename protection is off")
+	}
+
+	SetFilenameProtection(true)
+	if !IsAlwaysProtected("fixtures/.watchman.yml") {
+		t.Error("filename protection should be re-enabled")
+	}
+}
+
+func TestProtectorExtras(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr := NewProtector(&config.ProtectConfig{
+		Extra: []string{"~/.kube/", "/opt/secrets.env"},
+	})
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{
+			name: "hardcoded default still protected",
+			path: filepath.Join(home, ".ssh", "id_rsa"),
+			want: true,
+		},
+		{
+			name: "extra directory",
+			path: filepath.Join(home, ".kube", "config"),
+			want: true,
+		},
+		{
+			name: "extra file",
+			path: "/opt/secrets.env",
+			want: true,
+		},
+		{
+			name: "unrelated path",
+			path: "/tmp/scratch.txt",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pr.IsProtected(tt.path); got != tt.want {
+				t.Errorf("IsProtected(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectorNilReceiver(t *testing.T) {
+	var pr *Protector
+	if !pr.IsProtected("fixtures/.watchman.yml") {
+		t.Error("nil Protector should fall back to hardcoded defaults")
+	}
+}
+
+func TestProtectorFilenameToggle(t *testing.T) {
+	disabled := false
+	pr := NewProtector(&config.ProtectConfig{ConfigFilename: &disabled})
+
+	if pr.IsProtected("fixtures/.watchman.yml") {
+		t.Error("filename protection should be off when config_filename is false")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pr.IsProtected(filepath.Join(home, ".ssh", "id_rsa")) {
+		t.Error("path-based protection must remain active")
+	}
+}
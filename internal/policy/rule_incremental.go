@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"math"
 	"os/exec"
 	"strings"
 
@@ -11,19 +12,27 @@ import (
 type IncrementalRule struct {
 	MaxFiles  int
 	WarnRatio float64
-	countFunc func() int // injectable for testing
+	// DefaultRatio is used when WarnRatio is unset, letting the config
+	// set warn behavior once instead of per rule. 0 falls back to the
+	// built-in 70%.
+	DefaultRatio float64
+	GitOnError   string     // "deny" to fail closed when git is unavailable
+	countFunc    func() int // injectable for testing
 }
 
-// NewIncrementalRule creates a new incremental change rule.
-func NewIncrementalRule(cfg *config.IncrementalConfig) *IncrementalRule {
-	if cfg == nil {
-		return &IncrementalRule{countFunc: countGitModifiedFiles}
+// NewIncrementalRule creates a new incremental change rule. defaultWarnRatio
+// applies when the rule's own warn ratio is unset; 0 keeps the built-in
+// default.
+func NewIncrementalRule(cfg *config.IncrementalConfig, git *config.GitConfig, defaultWarnRatio float64) *IncrementalRule {
+	rule := &IncrementalRule{countFunc: countGitModifiedFiles, DefaultRatio: defaultWarnRatio}
+	if cfg != nil {
+		rule.MaxFiles = cfg.MaxFiles
+		rule.WarnRatio = cfg.WarnRatio
 	}
-	return &IncrementalRule{
-		MaxFiles:  cfg.MaxFiles,
-		WarnRatio: cfg.WarnRatio,
-		countFunc: countGitModifiedFiles,
+	if git != nil {
+		rule.GitOnError = git.OnError
 	}
+	return rule
 }
 
 // Evaluate checks if the current number of modified files exceeds limits.
@@ -34,6 +43,12 @@ func (r *IncrementalRule) Evaluate() Decision {
 
 	count := r.countModifiedFiles()
 	if count < 0 {
+		if r.GitOnError == "deny" {
+			return Decision{
+				Allowed: false,
+				Reason:  "cannot determine modified file count (git unavailable) and git.on_error is deny",
+			}
+		}
 		// Could not determine, allow to proceed
 		return Decision{Allowed: true}
 	}
@@ -58,13 +73,27 @@ func (r *IncrementalRule) Evaluate() Decision {
 	return Decision{Allowed: true}
 }
 
-// warnThreshold calculates when to start warning.
+// warnThreshold calculates when to start warning. The ratio is rounded to
+// the nearest file count so e.g. MaxFiles 10 with ratio 0.75 warns at 8, and
+// the result is clamped to stay at least 1 and below MaxFiles.
 func (r *IncrementalRule) warnThreshold() int {
-	if r.WarnRatio <= 0 || r.WarnRatio >= 1 {
-		// Default to 70% if not set or invalid
-		return int(float64(r.MaxFiles) * 0.7)
+	ratio := r.WarnRatio
+	if ratio <= 0 || ratio >= 1 {
+		// Fall back to the configured global default, then to 70%
+		ratio = r.DefaultRatio
+	}
+	if ratio <= 0 || ratio >= 1 {
+		ratio = 0.7
+	}
+
+	threshold := int(math.Round(float64(r.MaxFiles) * ratio))
+	if threshold < 1 {
+		threshold = 1
+	}
+	if threshold >= r.MaxFiles {
+		threshold = r.MaxFiles - 1
 	}
-	return int(float64(r.MaxFiles) * r.WarnRatio)
+	return threshold
 }
 
 // countModifiedFiles uses git status to count modified files.
@@ -26,7 +26,7 @@ func TestNewIncrementalRule(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rule := NewIncrementalRule(tt.cfg)
+			rule := NewIncrementalRule(tt.cfg, nil, 0)
 			if rule == nil {
 				t.Error("NewIncrementalRule returned nil")
 			}
@@ -74,6 +74,24 @@ func TestIncrementalRuleWarnThreshold(t *testing.T) {
 			warnRatio: -0.5,
 			want:      7,
 		},
+		{
+			name:      "half boundary rounds up",
+			maxFiles:  10,
+			warnRatio: 0.75,
+			want:      8, // 7.5 rounds to 8, not truncated to 7
+		},
+		{
+			name:      "small max clamps to at least 1",
+			maxFiles:  2,
+			warnRatio: 0.1,
+			want:      1,
+		},
+		{
+			name:      "threshold stays below max",
+			maxFiles:  2,
+			warnRatio: 0.9,
+			want:      1, // 1.8 rounds to 2, clamped below MaxFiles
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,6 +108,51 @@ func TestIncrementalRuleWarnThreshold(t *testing.T) {
 	}
 }
 
+func TestIncrementalRuleDefaultRatio(t *testing.T) {
+	tests := []struct {
+		name         string
+		warnRatio    float64
+		defaultRatio float64
+		want         int
+	}{
+		{
+			name:         "unset ratio uses global default",
+			warnRatio:    0,
+			defaultRatio: 0.5,
+			want:         5,
+		},
+		{
+			name:         "per-rule ratio wins over default",
+			warnRatio:    0.9,
+			defaultRatio: 0.5,
+			want:         9,
+		},
+		{
+			name:         "unset default falls back to 0.7",
+			warnRatio:    0,
+			defaultRatio: 0,
+			want:         7,
+		},
+		{
+			name:         "invalid default falls back to 0.7",
+			warnRatio:    0,
+			defaultRatio: 1.5,
+			want:         7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewIncrementalRule(
+				&config.IncrementalConfig{MaxFiles: 10, WarnRatio: tt.warnRatio},
+				nil, tt.defaultRatio)
+			if got := rule.warnThreshold(); got != tt.want {
+				t.Errorf("warnThreshold() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIncrementalRuleEvaluateDisabled(t *testing.T) {
 	// When MaxFiles is 0, rule is effectively disabled
 	rule := &IncrementalRule{MaxFiles: 0}
@@ -184,6 +247,49 @@ func TestIncrementalRuleEvaluate(t *testing.T) {
 	}
 }
 
+func TestIncrementalRuleGitOnError(t *testing.T) {
+	tests := []struct {
+		name        string
+		gitOnError  string
+		wantAllowed bool
+	}{
+		{
+			name:        "fail open by default",
+			gitOnError:  "",
+			wantAllowed: true,
+		},
+		{
+			name:        "fail closed when on_error is deny",
+			gitOnError:  "deny",
+			wantAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &IncrementalRule{
+				MaxFiles:   10,
+				GitOnError: tt.gitOnError,
+				countFunc:  func() int { return -1 },
+			}
+			decision := rule.Evaluate()
+			if decision.Allowed != tt.wantAllowed {
+				t.Errorf("Evaluate() allowed = %v, want %v", decision.Allowed, tt.wantAllowed)
+			}
+			if !tt.wantAllowed && decision.Reason == "" {
+				t.Error("Evaluate() should include a reason when denying")
+			}
+		})
+	}
+}
+
+func TestNewIncrementalRuleGitConfig(t *testing.T) {
+	rule := NewIncrementalRule(nil, &config.GitConfig{OnError: "deny"}, 0)
+	if rule.GitOnError != "deny" {
+		t.Errorf("GitOnError = %q, want %q", rule.GitOnError, "deny")
+	}
+}
+
 func TestIncrementalRuleCountModifiedFiles(t *testing.T) {
 	t.Run("uses countFunc when set", func(t *testing.T) {
 		rule := &IncrementalRule{
@@ -13,6 +13,8 @@ import (
 // InvariantsRule enforces declarative structural checks.
 type InvariantsRule struct {
 	cfg *config.InvariantsConfig
+	// Classes overrides the built-in tool classification; nil uses defaults.
+	Classes *ToolClasses
 }
 
 // NewInvariantsRule creates an invariants rule from config.
@@ -26,7 +28,7 @@ func NewInvariantsRule(cfg *config.InvariantsConfig) *InvariantsRule {
 // Evaluate checks if the file modification violates any invariants.
 // Only applies to modification tools (Write, Edit, NotebookEdit).
 func (r *InvariantsRule) Evaluate(toolName, filePath, content string) Decision {
-	if !writeTools[toolName] {
+	if !r.Classes.IsModifying(toolName) {
 		return Decision{Allowed: true}
 	}
 
@@ -58,6 +60,26 @@ func (r *InvariantsRule) Evaluate(toolName, filePath, content string) Decision {
 	return Decision{Allowed: true}
 }
 
+// EvaluateDelete checks whether deleting filePath would break a declared
+// coexistence pairing, leaving the required counterpart orphaned.
+func (r *InvariantsRule) EvaluateDelete(filePath string) Decision {
+	for _, check := range r.cfg.Coexistence {
+		if !glob.Match(filePath, check.If) {
+			continue
+		}
+
+		requiredPath := expandPlaceholders(check.Require, filePath)
+		if _, err := os.Stat(requiredPath); err == nil {
+			msg := check.Message
+			if msg == "" {
+				msg = "coexistence check failed: deleting " + filePath + " would orphan " + requiredPath
+			}
+			return Decision{Allowed: false, Reason: msg}
+		}
+	}
+	return Decision{Allowed: true}
+}
+
 // checkCoexistence ensures related files exist together.
 func (r *InvariantsRule) checkCoexistence(filePath string) Decision {
 	for _, check := range r.cfg.Coexistence {
@@ -424,3 +424,42 @@ func TestInvariantsErrorLowercase(t *testing.T) {
 		})
 	}
 }
+
+func TestInvariantsEvaluateDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	implFile := filepath.Join(tmpDir, "foo.go")
+	testFile := filepath.Join(tmpDir, "foo_test.go")
+
+	if err := os.WriteFile(implFile, []byte("package foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(testFile, []byte("package foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.InvariantsConfig{
+		Coexistence: []config.CoexistenceCheck{
+			{
+				Name:    "impl-pairs-with-test",
+				If:      "**/*.go",
+				Require: "${name}_test.go",
+			},
+		},
+	}
+	rule := NewInvariantsRule(cfg)
+
+	// Deleting the impl would orphan the test file
+	decision := rule.EvaluateDelete(implFile)
+	if decision.Allowed {
+		t.Error("expected delete to be denied while counterpart exists")
+	}
+
+	// Once the counterpart is gone, the delete is fine
+	if err := os.Remove(testFile); err != nil {
+		t.Fatal(err)
+	}
+	decision = rule.EvaluateDelete(implFile)
+	if !decision.Allowed {
+		t.Errorf("expected delete to be allowed without counterpart: %s", decision.Reason)
+	}
+}
@@ -10,17 +10,41 @@ import (
 	"github.com/adrianpk/watchman/internal/parser"
 )
 
-// writeTools are tools that modify files.
-var writeTools = map[string]bool{
-	"Write":        true,
-	"Edit":         true,
-	"NotebookEdit": true,
-}
-
 // ScopeToFiles restricts modifications to declared file patterns.
 type ScopeToFiles struct {
 	Allow []string
 	Block []string
+	// GoPackages holds Go package patterns (./internal/..., ./cmd/foo)
+	// resolved against the module root.
+	GoPackages []string
+	// TrackedOnly restricts modifications to paths in the git index.
+	TrackedOnly bool
+	// NewFilesOnly limits scope evaluation to paths that do not exist yet.
+	NewFilesOnly bool
+	// Exempt lists globs exempt from the allow/block/tracked evaluation.
+	Exempt []string
+	// Classes overrides the built-in tool classification; nil uses defaults.
+	Classes *ToolClasses
+
+	// Precompiled pattern matchers, built lazily on first evaluation so
+	// struct-literal construction and post-construction appends (session
+	// scope overrides) still work. Mutating the pattern slices after the
+	// first Evaluate is not supported.
+	allowMatcher  *glob.Matcher
+	blockMatcher  *glob.Matcher
+	exemptMatcher *glob.Matcher
+}
+
+// trackedFiles is the tracked-file lookup, injectable for tests.
+var trackedFiles = TrackedFiles
+
+// statPath checks path existence for new_files_only, injectable for tests.
+var statPath = os.Stat
+
+// pathExists reports whether the path already exists on disk.
+func pathExists(p string) bool {
+	_, err := statPath(p)
+	return err == nil
 }
 
 // NewScopeToFiles creates a scope rule from config.
@@ -29,19 +53,31 @@ func NewScopeToFiles(cfg *config.ScopeConfig) *ScopeToFiles {
 		return &ScopeToFiles{}
 	}
 	return &ScopeToFiles{
-		Allow: cfg.Allow,
-		Block: cfg.Block,
+		Allow:        cfg.Allow,
+		Block:        cfg.Block,
+		GoPackages:   cfg.GoPackages,
+		TrackedOnly:  cfg.TrackedOnly,
+		NewFilesOnly: cfg.NewFilesOnly,
+		Exempt:       cfg.Exempt,
 	}
 }
 
 // Evaluate checks if the command modifies files within the defined scope.
 func (r *ScopeToFiles) Evaluate(toolName string, cmd parser.Command) Decision {
-	if !writeTools[toolName] {
+	if !r.Classes.IsModifying(toolName) {
 		return Decision{Allowed: true}
 	}
 
 	paths := collectPathCandidates(cmd)
 	for _, p := range paths {
+		// Under new_files_only, editing an existing file is out of
+		// scope's business; only creation is confined.
+		if r.NewFilesOnly && pathExists(p) {
+			continue
+		}
+		if r.isExempt(p) {
+			continue
+		}
 		if r.isBlocked(p) {
 			return Decision{
 				Allowed: false,
@@ -54,11 +90,48 @@ func (r *ScopeToFiles) Evaluate(toolName string, cmd parser.Command) Decision {
 				Reason:  "scope.allow: " + p + " does not match any allowed pattern " + r.summarizeAllow(),
 			}
 		}
+		if r.TrackedOnly && !isTracked(p) {
+			return Decision{
+				Allowed: false,
+				Reason:  "scope.tracked_only: " + p + " is not tracked by git",
+			}
+		}
 	}
 
 	return Decision{Allowed: true}
 }
 
+// compile builds the pattern matchers once; subsequent calls are no-ops
+// unless a pattern list was swapped for one of a different length.
+func (r *ScopeToFiles) compile() {
+	if r.allowMatcher == nil || r.allowMatcher.Len() != len(r.Allow) {
+		r.allowMatcher = glob.Compile(r.Allow)
+	}
+	if r.blockMatcher == nil || r.blockMatcher.Len() != len(r.Block) {
+		r.blockMatcher = glob.Compile(r.Block)
+	}
+	if r.exemptMatcher == nil || r.exemptMatcher.Len() != len(r.Exempt) {
+		r.exemptMatcher = glob.Compile(r.Exempt)
+	}
+}
+
+// isTracked checks the path against the git index, failing open when git is
+// unavailable (mirroring the incremental rule's behavior).
+func isTracked(p string) bool {
+	tracked := trackedFiles()
+	if tracked == nil {
+		return true
+	}
+
+	rel := filepath.Clean(toRelativePath(p))
+	for _, t := range tracked {
+		if t == rel {
+			return true
+		}
+	}
+	return false
+}
+
 // summarizeAllow returns a short summary of allowed patterns for error messages.
 func (r *ScopeToFiles) summarizeAllow() string {
 	if len(r.Allow) == 0 {
@@ -70,24 +143,102 @@ func (r *ScopeToFiles) summarizeAllow() string {
 	return "(" + strings.Join(r.Allow[:5], ", ") + ", ...)"
 }
 
+// isExempt checks if a path matches an exempt pattern. Exempt paths skip
+// the allow/block/tracked checks entirely; workspace confinement and path
+// protection still apply elsewhere in the pipeline.
+func (r *ScopeToFiles) isExempt(p string) bool {
+	if len(r.Exempt) == 0 {
+		return false
+	}
+	r.compile()
+	return r.exemptMatcher.Match(p) || r.exemptMatcher.Match(toRelativePath(p))
+}
+
 // isBlocked checks if a path matches any block pattern.
 func (r *ScopeToFiles) isBlocked(p string) bool {
-	return glob.MatchAny(p, r.Block)
+	r.compile()
+	return r.blockMatcher.Match(p)
 }
 
 // isInScope checks if a path is within the allowed scope.
 // If no allow patterns are defined, all paths are in scope.
 func (r *ScopeToFiles) isInScope(p string) bool {
-	if len(r.Allow) == 0 {
+	if len(r.Allow) == 0 && len(r.GoPackages) == 0 {
 		return true
 	}
+	r.compile()
 
 	// Normalize path to relative for glob matching
 	// This allows patterns like "src/**/*.go" to match absolute paths
 	relPath := toRelativePath(p)
 
 	// Try both the original path and the relative version
-	return glob.MatchAny(p, r.Allow) || glob.MatchAny(relPath, r.Allow)
+	if r.allowMatcher.Match(p) || r.allowMatcher.Match(relPath) {
+		return true
+	}
+	return r.inGoPackages(relPath)
+}
+
+// inGoPackages checks the path against scope.go_packages patterns, resolved
+// relative to the module root (the directory holding go.mod).
+func (r *ScopeToFiles) inGoPackages(p string) bool {
+	if len(r.GoPackages) == 0 {
+		return false
+	}
+
+	rel := moduleRelativePath(p)
+	dir := filepath.Dir(rel)
+
+	for _, pattern := range r.GoPackages {
+		if matchGoPackage(dir, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGoPackage matches a module-relative directory against one Go package
+// pattern. A trailing /... matches the package and everything beneath it;
+// otherwise the directory must match exactly.
+func matchGoPackage(dir, pattern string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+
+	if strings.HasSuffix(pattern, "/...") {
+		root := filepath.Clean(strings.TrimSuffix(pattern, "/..."))
+		return dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))
+	}
+
+	return dir == filepath.Clean(pattern)
+}
+
+// moduleRelativePath converts a path to be relative to the module root,
+// found by walking up from the working directory to the nearest go.mod.
+// Without a go.mod the path is returned unchanged.
+func moduleRelativePath(p string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+
+	abs := p
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(cwd, p)
+	}
+
+	root := cwd
+	for {
+		if _, err := os.Stat(filepath.Join(root, "go.mod")); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				return rel
+			}
+			return p
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return p
+		}
+		root = parent
+	}
 }
 
 // toRelativePath converts an absolute path to relative (if within cwd).
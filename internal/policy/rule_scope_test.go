@@ -273,3 +273,183 @@ func TestToRelativePath(t *testing.T) {
 }
 
 // Note: matchGlob and matchDoublestar tests are now in internal/glob/glob_test.go
+
+func TestScopeToFilesDirectoryBlock(t *testing.T) {
+	rule := &ScopeToFiles{
+		Block: []string{"vendor/"},
+	}
+
+	tests := []struct {
+		path    string
+		blocked bool
+	}{
+		{"vendor/lib.go", true},
+		{"vendor/a/b/lib.go", true},
+		{"vendor", true},
+		{"src/main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := rule.isBlocked(tt.path); got != tt.blocked {
+				t.Errorf("isBlocked(%q) = %v, want %v", tt.path, got, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestMatchGoPackage(t *testing.T) {
+	tests := []struct {
+		dir     string
+		pattern string
+		want    bool
+	}{
+		{"internal/foo", "./internal/...", true},
+		{"internal/foo/bar", "./internal/...", true},
+		{"internal", "./internal/...", true},
+		{"pkg", "./internal/...", false},
+		{"cmd/foo", "./cmd/foo", true},
+		{"cmd/foo/sub", "./cmd/foo", false},
+		{"cmd/foobar", "./cmd/foo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir+" "+tt.pattern, func(t *testing.T) {
+			got := matchGoPackage(tt.dir, tt.pattern)
+			if got != tt.want {
+				t.Errorf("matchGoPackage(%q, %q) = %v, want %v", tt.dir, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeGoPackages(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/proj\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &ScopeToFiles{
+		GoPackages: []string{"./internal/..."},
+	}
+
+	if !rule.isInScope("internal/foo/server.go") {
+		t.Error("expected internal/foo to match ./internal/...")
+	}
+	if rule.isInScope("pkg/util.go") {
+		t.Error("expected pkg to be out of scope")
+	}
+}
+
+func TestScopeTrackedOnly(t *testing.T) {
+	orig := trackedFiles
+	defer func() { trackedFiles = orig }()
+	trackedFiles = func() []string {
+		return []string{"main.go", "docs/readme.md"}
+	}
+
+	rule := &ScopeToFiles{TrackedOnly: true}
+
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"scratch.go"}}); d.Allowed {
+		t.Error("write of untracked file should be denied")
+	}
+	if d := rule.Evaluate("Edit", parser.Command{Args: []string{"main.go"}}); !d.Allowed {
+		t.Errorf("edit of tracked file should be allowed: %s", d.Reason)
+	}
+	if d := rule.Evaluate("Read", parser.Command{Args: []string{"scratch.go"}}); !d.Allowed {
+		t.Errorf("non-modifying tool should not be restricted: %s", d.Reason)
+	}
+
+	// Fail open when git is unavailable.
+	trackedFiles = func() []string { return nil }
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"scratch.go"}}); !d.Allowed {
+		t.Errorf("expected fail-open without git: %s", d.Reason)
+	}
+}
+
+func TestScopeExempt(t *testing.T) {
+	rule := &ScopeToFiles{
+		Allow:  []string{"src/**/*.go"},
+		Block:  []string{"gen/legacy/**"},
+		Exempt: []string{"gen/**"},
+	}
+
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"gen/api.pb.go"}}); !d.Allowed {
+		t.Errorf("exempt generated path should be writable despite allow list: %s", d.Reason)
+	}
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"gen/legacy/old.pb.go"}}); !d.Allowed {
+		t.Errorf("exempt should take precedence over block: %s", d.Reason)
+	}
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"docs/readme.md"}}); d.Allowed {
+		t.Error("non-exempt path outside allow list should still be denied")
+	}
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"src/main.go"}}); !d.Allowed {
+		t.Errorf("allowed path should stay writable: %s", d.Reason)
+	}
+}
+
+func TestScopeExemptTrackedOnly(t *testing.T) {
+	orig := trackedFiles
+	defer func() { trackedFiles = orig }()
+	trackedFiles = func() []string { return []string{"main.go"} }
+
+	rule := &ScopeToFiles{
+		TrackedOnly: true,
+		Exempt:      []string{"gen/**"},
+	}
+
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"gen/api.pb.go"}}); !d.Allowed {
+		t.Errorf("exempt path should bypass tracked_only: %s", d.Reason)
+	}
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"scratch.go"}}); d.Allowed {
+		t.Error("untracked non-exempt file should still be denied")
+	}
+}
+
+func TestScopeNewFilesOnly(t *testing.T) {
+	orig := statPath
+	defer func() { statPath = orig }()
+	existing := map[string]bool{"docs/readme.md": true}
+	statPath = func(p string) (os.FileInfo, error) {
+		if existing[p] {
+			return nil, nil
+		}
+		return nil, os.ErrNotExist
+	}
+
+	rule := &ScopeToFiles{
+		Allow:        []string{"src/**/*.go"},
+		NewFilesOnly: true,
+	}
+
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"docs/notes.md"}}); d.Allowed {
+		t.Error("creating a new out-of-scope file should be denied")
+	}
+	if d := rule.Evaluate("Edit", parser.Command{Args: []string{"docs/readme.md"}}); !d.Allowed {
+		t.Errorf("editing an existing out-of-scope file should be allowed: %s", d.Reason)
+	}
+	if d := rule.Evaluate("Write", parser.Command{Args: []string{"src/app/new.go"}}); !d.Allowed {
+		t.Errorf("creating a new in-scope file should be allowed: %s", d.Reason)
+	}
+}
+
+func TestScopeNewFilesOnlyDisabled(t *testing.T) {
+	orig := statPath
+	defer func() { statPath = orig }()
+	statPath = func(p string) (os.FileInfo, error) { return nil, nil }
+
+	rule := &ScopeToFiles{Allow: []string{"src/**/*.go"}}
+
+	if d := rule.Evaluate("Edit", parser.Command{Args: []string{"docs/readme.md"}}); d.Allowed {
+		t.Error("without new_files_only, existing out-of-scope files stay denied")
+	}
+}
@@ -6,15 +6,20 @@ import (
 	"unicode"
 
 	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/parser"
 )
 
 // VersioningRule validates commit messages and branch protection.
 type VersioningRule struct {
-	Commit     config.CommitConfig
-	Branches   config.BranchesConfig
-	Operations config.OperationsConfig
-	Workflow   string
-	Tool       string
+	Commit              config.CommitConfig
+	Branches            config.BranchesConfig
+	Operations          config.OperationsConfig
+	Workflow            string
+	Tool                string
+	ToolPolicy          string
+	AllowInlineOverride bool
+	ForbidNoVerify      bool
+	AllowedRemotes      []string
 }
 
 // NewVersioningRule creates a versioning rule from config.
@@ -23,24 +28,71 @@ func NewVersioningRule(cfg *config.VersioningConfig) *VersioningRule {
 		return &VersioningRule{}
 	}
 	return &VersioningRule{
-		Commit:     cfg.Commit,
-		Branches:   cfg.Branches,
-		Operations: cfg.Operations,
-		Workflow:   cfg.Workflow,
-		Tool:       cfg.Tool,
+		Commit:              cfg.Commit,
+		Branches:            cfg.Branches,
+		Operations:          cfg.Operations,
+		Workflow:            cfg.Workflow,
+		Tool:                cfg.Tool,
+		ToolPolicy:          cfg.ToolPolicy,
+		AllowInlineOverride: cfg.AllowInlineOverride,
+		ForbidNoVerify:      cfg.ForbidNoVerify,
+		AllowedRemotes:      cfg.AllowedRemotes,
 	}
 }
 
-// Evaluate checks if a git/jj command is allowed.
+// Evaluate checks if a git/jj command is allowed. Chained commands are split
+// into segments and each git segment is evaluated independently, carrying
+// branch context forward so a checkout earlier in the chain informs a later
+// commit.
 func (r *VersioningRule) Evaluate(command string) Decision {
+	segments := parser.SplitSegments(command)
+	if len(segments) > 1 {
+		var contextBranch, warning string
+		for _, seg := range segments {
+			seg = strings.TrimSpace(seg)
+			if b := checkoutTargetBranch(seg); b != "" {
+				contextBranch = b
+			}
+			decision := r.evaluateSegment(seg, contextBranch)
+			if !decision.Allowed {
+				return decision
+			}
+			if warning == "" {
+				warning = decision.Warning
+			}
+		}
+		return Decision{Allowed: true, Warning: warning}
+	}
+
+	return r.evaluateSegment(command, "")
+}
+
+// evaluateSegment runs every versioning check against a single command
+// segment. contextBranch is the branch a preceding checkout in the same
+// chain switched to, or "".
+func (r *VersioningRule) evaluateSegment(command, contextBranch string) Decision {
 	if !isGitCommand(command) {
 		return Decision{Allowed: true}
 	}
 
+	if r.Tool == "jj" && r.ToolPolicy == "require" {
+		parsed := parser.Parse(command)
+		if parsed.Program == "git" && gitMutatingSubcommands[parsed.Subcommand] {
+			return Decision{
+				Allowed: false,
+				Reason:  "versioning.tool requires jj: git " + parsed.Subcommand + " is not permitted",
+			}
+		}
+	}
+
 	if blocked := r.isBlockedOperation(command); blocked != "" {
-		return Decision{
-			Allowed: false,
-			Reason:  "operation blocked by configuration: " + blocked,
+		// A trailing "# watchman:force" comment overrides the operation
+		// block for this one command when enabled in config.
+		if !(r.AllowInlineOverride && hasInlineOverride(command)) {
+			return Decision{
+				Allowed: false,
+				Reason:  "operation blocked by configuration: " + blocked,
+			}
 		}
 	}
 
@@ -51,13 +103,49 @@ func (r *VersioningRule) Evaluate(command string) Decision {
 		}
 	}
 
+	if r.ForbidNoVerify {
+		if reason := findNoVerify(command); reason != "" {
+			return Decision{
+				Allowed: false,
+				Reason:  reason,
+			}
+		}
+	}
+
+	if len(r.AllowedRemotes) > 0 {
+		if remote := pushRemote(command); remote != "" && !r.isAllowedRemote(remote) {
+			return Decision{
+				Allowed: false,
+				Reason:  "push to remote " + remote + " is not in versioning.allowed_remotes",
+			}
+		}
+	}
+
 	if isCommitCommand(command) {
-		return r.EvaluateCommit(command)
+		return r.evaluateCommit(command, contextBranch)
 	}
 
 	return Decision{Allowed: true}
 }
 
+// checkoutTargetBranch returns the branch a git checkout/switch segment
+// moves to, or "" for other commands.
+func checkoutTargetBranch(segment string) string {
+	parsed := parser.Parse(segment)
+	if parsed.Program != "git" {
+		return ""
+	}
+	if parsed.Subcommand != "checkout" && parsed.Subcommand != "switch" {
+		return ""
+	}
+	for _, arg := range parsed.Args {
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
+
 func (r *VersioningRule) violatesWorkflow(cmd string) string {
 	switch r.Workflow {
 	case "linear":
@@ -81,27 +169,129 @@ func (r *VersioningRule) isBlockedOperation(cmd string) string {
 	return ""
 }
 
+// pushRemote returns the remote a git push targets (the first non-flag
+// argument after push), or "" when no remote is spelled out. Pushes relying
+// on the configured default remote are deliberately not checked.
+func pushRemote(cmd string) string {
+	parsed := parser.Parse(cmd)
+	if parsed.Program != "git" || parsed.Subcommand != "push" {
+		return ""
+	}
+
+	// Scan the raw tokens after push, skipping flags, so value-less flags
+	// like --force-with-lease don't swallow the remote.
+	fields := strings.Fields(cmd)
+	for i, f := range fields {
+		if f != "push" {
+			continue
+		}
+		for _, tok := range fields[i+1:] {
+			if !strings.HasPrefix(tok, "-") {
+				return tok
+			}
+		}
+		break
+	}
+	return ""
+}
+
+func (r *VersioningRule) isAllowedRemote(remote string) bool {
+	for _, allowed := range r.AllowedRemotes {
+		if allowed == remote {
+			return true
+		}
+	}
+	return false
+}
+
+// findNoVerify reports a git commit or push that skips local git hooks via
+// --no-verify (or commit's -n shorthand), or "" when neither is present. The
+// flag is detected on the parsed command to avoid substring false positives.
+func findNoVerify(cmd string) string {
+	parsed := parser.Parse(cmd)
+	if parsed.Program != "git" {
+		return ""
+	}
+
+	switch parsed.Subcommand {
+	case "commit":
+		if parsed.HasFlag("--no-verify") || parsed.HasFlag("-n") {
+			return "git commit --no-verify skips local git hooks (versioning.forbid_no_verify)"
+		}
+	case "push":
+		// For push, -n is --dry-run, so only the long form bypasses hooks.
+		if parsed.HasFlag("--no-verify") {
+			return "git push --no-verify skips local git hooks (versioning.forbid_no_verify)"
+		}
+	}
+	return ""
+}
+
+// gitMutatingSubcommands lists the git subcommands that change history or
+// the working tree, denied wholesale under tool_policy: require.
+var gitMutatingSubcommands = map[string]bool{
+	"commit": true, "push": true, "merge": true, "rebase": true,
+	"reset": true, "checkout": true, "add": true, "rm": true,
+	"tag": true, "cherry-pick": true, "stash": true,
+}
+
 func isGitCommand(cmd string) bool {
 	return strings.Contains(cmd, "git ") || strings.Contains(cmd, "jj ")
 }
 
+// inlineOverrideMarker is the trailing comment that requests a one-time
+// bypass of the versioning operation block.
+const inlineOverrideMarker = "# watchman:force"
+
+func hasInlineOverride(cmd string) bool {
+	return strings.HasSuffix(strings.TrimSpace(cmd), inlineOverrideMarker)
+}
+
 // EvaluateCommit checks if a commit command is allowed.
 func (r *VersioningRule) EvaluateCommit(command string) Decision {
+	return r.evaluateCommit(command, "")
+}
+
+// evaluateCommit validates a commit segment. When the command itself names
+// no branch, contextBranch (from a checkout earlier in the chain) is used
+// for branch protection.
+func (r *VersioningRule) evaluateCommit(command, contextBranch string) Decision {
 	if !isCommitCommand(command) {
 		return Decision{Allowed: true}
 	}
 
 	if r.Tool == "jj" && strings.Contains(command, "git commit") {
+		reason := "prefer jj over git: use 'jj commit' instead of 'git commit'"
+		// Under tool_policy: prefer the mismatch is advisory, not a block.
+		if r.ToolPolicy == "prefer" {
+			return Decision{Allowed: true, Warning: reason}
+		}
 		return Decision{
 			Allowed: false,
-			Reason:  "prefer jj over git: use 'jj commit' instead of 'git commit'",
+			Reason:  reason,
+		}
+	}
+
+	// Note: a globally configured commit.gpgsign cannot be detected here, so
+	// require_signed insists on an explicit signing flag on the command.
+	if r.Commit.RequireSigned && strings.Contains(command, "git commit") {
+		parsed := parser.Parse(command)
+		if !parsed.HasFlag("-S") && !parsed.HasFlag("--gpg-sign") {
+			return Decision{
+				Allowed: false,
+				Reason:  "commit must be signed: use -S/--gpg-sign",
+			}
 		}
 	}
 
 	branch := extractBranchFromCommand(command)
+	if branch == "" {
+		branch = contextBranch
+	}
 	if r.isProtectedBranch(branch) {
 		return Decision{
 			Allowed: false,
+			Ask:     r.Branches.OnProtected == "ask",
 			Reason:  "cannot commit directly to protected branch: " + branch,
 		}
 	}
@@ -118,6 +308,16 @@ func (r *VersioningRule) EvaluateCommit(command string) Decision {
 		}
 	}
 
+	if r.Commit.MinLength > 0 {
+		subject := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+		if len(subject) < r.Commit.MinLength {
+			return Decision{
+				Allowed: false,
+				Reason:  "commit subject is shorter than min length of " + itoa(r.Commit.MinLength),
+			}
+		}
+	}
+
 	if r.Commit.RequireUppercase && len(message) > 0 {
 		first := rune(message[0])
 		if !unicode.IsUpper(first) && unicode.IsLetter(first) {
@@ -156,6 +356,24 @@ func (r *VersioningRule) EvaluateCommit(command string) Decision {
 		}
 	}
 
+	if len(r.Commit.AllowedScopes) > 0 {
+		subject := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+		scope := conventionalScope(subject)
+		if scope == "" {
+			if r.Commit.RequireScope {
+				return Decision{
+					Allowed: false,
+					Reason:  "commit must declare a conventional scope (allowed: " + strings.Join(r.Commit.AllowedScopes, ", ") + ")",
+				}
+			}
+		} else if !r.isAllowedScope(scope) {
+			return Decision{
+				Allowed: false,
+				Reason:  "commit scope " + scope + " is not allowed (allowed: " + strings.Join(r.Commit.AllowedScopes, ", ") + ")",
+			}
+		}
+	}
+
 	if r.Commit.PrefixPattern != "" {
 		re, err := regexp.Compile("^" + r.Commit.PrefixPattern)
 		if err == nil && !re.MatchString(message) {
@@ -169,6 +387,29 @@ func (r *VersioningRule) EvaluateCommit(command string) Decision {
 	return Decision{Allowed: true}
 }
 
+// conventionalScopePattern matches a conventional-commit subject prefix
+// like "feat(api):" or "fix!:", capturing the scope when present.
+var conventionalScopePattern = regexp.MustCompile(`^[a-z]+(?:\(([^)]*)\))?!?:`)
+
+// conventionalScope extracts the scope from a conventional-commit subject,
+// returning "" for subjects without one (including non-conventional ones).
+func conventionalScope(subject string) string {
+	m := conventionalScopePattern.FindStringSubmatch(subject)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+func (r *VersioningRule) isAllowedScope(scope string) bool {
+	for _, s := range r.Commit.AllowedScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *VersioningRule) isProtectedBranch(branch string) bool {
 	for _, p := range r.Branches.Protected {
 		if p == branch {
@@ -179,7 +420,11 @@ func (r *VersioningRule) isProtectedBranch(branch string) bool {
 }
 
 func isCommitCommand(cmd string) bool {
-	return strings.Contains(cmd, "git commit") || strings.Contains(cmd, "jj commit")
+	// jj sets commit messages via describe and new as well as commit.
+	return strings.Contains(cmd, "git commit") ||
+		strings.Contains(cmd, "jj commit") ||
+		strings.Contains(cmd, "jj describe") ||
+		strings.Contains(cmd, "jj new")
 }
 
 func extractBranchFromCommand(cmd string) string {
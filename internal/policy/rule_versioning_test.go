@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/adrianpk/watchman/internal/config"
@@ -121,6 +122,110 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestVersioningRuleChainedSegments(t *testing.T) {
+	rule := &VersioningRule{
+		Branches: config.BranchesConfig{
+			Protected: []string{"main"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		command     string
+		wantAllowed bool
+	}{
+		{
+			name:        "checkout then commit on protected branch",
+			command:     `git checkout main && git commit -m "Fix"`,
+			wantAllowed: false,
+		},
+		{
+			name:        "switch then commit on protected branch",
+			command:     `git switch main; git commit -m "Fix"`,
+			wantAllowed: false,
+		},
+		{
+			name:        "checkout feature then commit",
+			command:     `git checkout feature/x && git commit -m "Fix"`,
+			wantAllowed: true,
+		},
+		{
+			name:        "later checkout overrides earlier",
+			command:     `git checkout main && git checkout feature/x && git commit -m "Fix"`,
+			wantAllowed: true,
+		},
+		{
+			name:        "commit before checkout is unaffected",
+			command:     `git commit -m "Fix" && git checkout main`,
+			wantAllowed: true,
+		},
+		{
+			name:        "non-git segment in chain ignored",
+			command:     `make build && git commit -m "Fix"`,
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rule.Evaluate(tt.command)
+			if got.Allowed != tt.wantAllowed {
+				t.Errorf("Evaluate(%q) = %v, want %v, reason: %s", tt.command, got.Allowed, tt.wantAllowed, got.Reason)
+			}
+		})
+	}
+}
+
+func TestVersioningRuleToolPolicy(t *testing.T) {
+	t.Run("prefer warns but allows git commit", func(t *testing.T) {
+		rule := &VersioningRule{Tool: "jj", ToolPolicy: "prefer"}
+		decision := rule.Evaluate(`git commit -m "Fix"`)
+		if !decision.Allowed {
+			t.Fatalf("expected allow under prefer, got deny: %s", decision.Reason)
+		}
+		if !strings.Contains(decision.Warning, "prefer jj over git") {
+			t.Errorf("warning = %q, want jj preference warning", decision.Warning)
+		}
+	})
+
+	t.Run("default denies git commit", func(t *testing.T) {
+		rule := &VersioningRule{Tool: "jj"}
+		if decision := rule.Evaluate(`git commit -m "Fix"`); decision.Allowed {
+			t.Error("expected deny without tool_policy")
+		}
+	})
+
+	t.Run("require denies mutating git subcommands", func(t *testing.T) {
+		rule := &VersioningRule{Tool: "jj", ToolPolicy: "require"}
+		for _, cmd := range []string{
+			`git commit -m "Fix"`,
+			"git push origin main",
+			"git rebase main",
+			"git add .",
+		} {
+			if decision := rule.Evaluate(cmd); decision.Allowed {
+				t.Errorf("expected deny under require: %q", cmd)
+			}
+		}
+	})
+
+	t.Run("require allows read-only git", func(t *testing.T) {
+		rule := &VersioningRule{Tool: "jj", ToolPolicy: "require"}
+		for _, cmd := range []string{"git status", "git log --oneline", "git diff"} {
+			if decision := rule.Evaluate(cmd); !decision.Allowed {
+				t.Errorf("expected allow under require: %q (%s)", cmd, decision.Reason)
+			}
+		}
+	})
+
+	t.Run("require allows jj commands", func(t *testing.T) {
+		rule := &VersioningRule{Tool: "jj", ToolPolicy: "require"}
+		if decision := rule.Evaluate(`jj commit -m "Fix"`); !decision.Allowed {
+			t.Errorf("expected allow for jj: %s", decision.Reason)
+		}
+	})
+}
+
 func TestEvaluateCommit(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -186,6 +291,97 @@ func TestEvaluateCommit(t *testing.T) {
 			command:     `git commit -m "Short message"`,
 			wantAllowed: true,
 		},
+		{
+			name: "min length too short",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					MinLength: 10,
+				},
+			},
+			command:     `git commit -m "wip"`,
+			wantAllowed: false,
+		},
+		{
+			name: "min length ok",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					MinLength: 10,
+				},
+			},
+			command:     `git commit -m "Fix watcher race"`,
+			wantAllowed: true,
+		},
+		{
+			name: "min length zero disables check",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					MinLength: 0,
+				},
+			},
+			command:     `git commit -m "wip"`,
+			wantAllowed: true,
+		},
+		{
+			name: "min length trims subject whitespace",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					MinLength: 10,
+				},
+			},
+			command:     `git commit -m "  fix    "`,
+			wantAllowed: false,
+		},
+		{
+			name: "allowed scope passes",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					AllowedScopes: []string{"api", "ui", "db"},
+				},
+			},
+			command:     `git commit -m "feat(api): add pagination"`,
+			wantAllowed: true,
+		},
+		{
+			name: "disallowed scope denied",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					AllowedScopes: []string{"api", "ui", "db"},
+				},
+			},
+			command:     `git commit -m "feat(random): add pagination"`,
+			wantAllowed: false,
+		},
+		{
+			name: "missing scope allowed without require_scope",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					AllowedScopes: []string{"api", "ui", "db"},
+				},
+			},
+			command:     `git commit -m "feat: add pagination"`,
+			wantAllowed: true,
+		},
+		{
+			name: "missing scope denied with require_scope",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					AllowedScopes: []string{"api", "ui", "db"},
+					RequireScope:  true,
+				},
+			},
+			command:     `git commit -m "feat: add pagination"`,
+			wantAllowed: false,
+		},
+		{
+			name: "empty allowed scopes disables check",
+			rule: &VersioningRule{
+				Commit: config.CommitConfig{
+					RequireScope: true,
+				},
+			},
+			command:     `git commit -m "feat(random): add pagination"`,
+			wantAllowed: true,
+		},
 		{
 			name: "require uppercase fails",
 			rule: &VersioningRule{
@@ -373,6 +569,8 @@ func TestIsCommitCommand(t *testing.T) {
 	}{
 		{"git commit -m 'test'", true},
 		{"jj commit -m 'test'", true},
+		{"jj describe -m 'test'", true},
+		{"jj new -m 'test'", true},
 		{"git status", false},
 		{"git push", false},
 		{"ls -la", false},
@@ -436,3 +634,172 @@ func TestItoa(t *testing.T) {
 		})
 	}
 }
+
+func TestVersioningRuleRequireSigned(t *testing.T) {
+	rule := &VersioningRule{
+		Commit: config.CommitConfig{RequireSigned: true},
+	}
+
+	tests := []struct {
+		name    string
+		cmd     string
+		allowed bool
+	}{
+		{"unsigned commit denied", `git commit -m "Add feature"`, false},
+		{"short signing flag allowed", `git commit -S -m "Add feature"`, true},
+		{"long signing flag allowed", `git commit --gpg-sign -m "Add feature"`, true},
+		{"non-commit command unaffected", "git status", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rule.Evaluate(tt.cmd)
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Evaluate(%q) allowed = %v, want %v (%s)", tt.cmd, decision.Allowed, tt.allowed, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateCommitJJDescribe(t *testing.T) {
+	rule := &VersioningRule{
+		Commit: config.CommitConfig{RequireUppercase: true},
+	}
+
+	decision := rule.EvaluateCommit(`jj describe -m "lowercase message"`)
+	if decision.Allowed {
+		t.Error("expected jj describe with lowercase message to be denied")
+	}
+
+	decision = rule.EvaluateCommit(`jj new -m "lowercase message"`)
+	if decision.Allowed {
+		t.Error("expected jj new with lowercase message to be denied")
+	}
+
+	decision = rule.EvaluateCommit(`jj describe -m "Uppercase message"`)
+	if !decision.Allowed {
+		t.Errorf("expected jj describe with uppercase message to be allowed: %s", decision.Reason)
+	}
+}
+
+func TestEvaluateCommitProtectedBranchAsk(t *testing.T) {
+	tests := []struct {
+		name        string
+		onProtected string
+		wantAsk     bool
+	}{
+		{
+			name:        "deny by default",
+			onProtected: "",
+			wantAsk:     false,
+		},
+		{
+			name:        "deny when explicit",
+			onProtected: "deny",
+			wantAsk:     false,
+		},
+		{
+			name:        "ask when configured",
+			onProtected: "ask",
+			wantAsk:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &VersioningRule{
+				Branches: config.BranchesConfig{
+					Protected:   []string{"main"},
+					OnProtected: tt.onProtected,
+				},
+			}
+			decision := rule.EvaluateCommit(`git commit -m "Fix" -b main`)
+			if decision.Allowed {
+				t.Fatal("protected-branch commit should not be allowed outright")
+			}
+			if decision.Ask != tt.wantAsk {
+				t.Errorf("Ask = %v, want %v", decision.Ask, tt.wantAsk)
+			}
+		})
+	}
+}
+
+func TestEvaluateInlineOverride(t *testing.T) {
+	command := "git push --force origin main # watchman:force"
+
+	rule := &VersioningRule{
+		Operations: config.OperationsConfig{
+			Block: []string{"push --force"},
+		},
+	}
+	if decision := rule.Evaluate(command); decision.Allowed {
+		t.Error("expected override to be ignored when allow_inline_override is off")
+	}
+
+	rule.AllowInlineOverride = true
+	if decision := rule.Evaluate(command); !decision.Allowed {
+		t.Errorf("expected override to bypass operation block: %s", decision.Reason)
+	}
+
+	// The marker must be a trailing comment, not buried mid-command
+	if decision := rule.Evaluate("git push --force origin main"); decision.Allowed {
+		t.Error("expected block without override marker")
+	}
+}
+
+func TestVersioningRuleForbidNoVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		allowed bool
+	}{
+		{"commit no-verify", `git commit --no-verify -m "x"`, false},
+		{"commit shorthand", `git commit -n -m "x"`, false},
+		{"push no-verify", "git push --no-verify origin main", false},
+		{"plain commit", `git commit -m "x"`, true},
+		{"plain push", "git push origin main", true},
+		{"no-verify in message", `git commit -m "use --no-verify sparingly"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &VersioningRule{ForbidNoVerify: true}
+			decision := rule.Evaluate(tt.command)
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Evaluate(%q) allowed = %v, want %v (%s)",
+					tt.command, decision.Allowed, tt.allowed, decision.Reason)
+			}
+		})
+	}
+
+	// The flag is tolerated when forbid_no_verify is off.
+	rule := &VersioningRule{}
+	if decision := rule.Evaluate(`git commit --no-verify -m "x"`); !decision.Allowed {
+		t.Errorf("expected no-verify to pass without forbid_no_verify: %s", decision.Reason)
+	}
+}
+
+func TestVersioningRuleAllowedRemotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		allowed bool
+	}{
+		{"listed remote", "git push origin main", true},
+		{"unlisted remote", "git push evil main", false},
+		{"no remote fails open", "git push", true},
+		{"force flag before remote", "git push --force-with-lease origin main", true},
+		{"non-push untouched", "git fetch upstream", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &VersioningRule{AllowedRemotes: []string{"origin"}}
+			decision := rule.Evaluate(tt.command)
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Evaluate(%q) allowed = %v, want %v (%s)",
+					tt.command, decision.Allowed, tt.allowed, decision.Reason)
+			}
+		})
+	}
+}
@@ -13,6 +13,15 @@ import (
 type ConfineToWorkspace struct {
 	Allow []string
 	Block []string
+	// StripRootPrefix normalizes relative paths that repeat the workspace
+	// directory name before boundary checks.
+	StripRootPrefix bool
+	// BlockWorktrees denies git worktree creation outright instead of only
+	// when the new working tree lands outside the workspace.
+	BlockWorktrees bool
+	// Protector overrides the default always-protected set when set by the
+	// caller; nil falls back to the hardcoded defaults.
+	Protector *Protector
 }
 
 // NewConfineToWorkspace creates a workspace rule from config.
@@ -21,17 +30,65 @@ func NewConfineToWorkspace(cfg *config.WorkspaceConfig) *ConfineToWorkspace {
 		return &ConfineToWorkspace{}
 	}
 	return &ConfineToWorkspace{
-		Allow: cfg.Allow,
-		Block: cfg.Block,
+		Allow:           cfg.Allow,
+		Block:           cfg.Block,
+		StripRootPrefix: cfg.StripRootPrefix,
+		BlockWorktrees:  cfg.BlockWorktrees,
 	}
 }
 
 // Evaluate checks if the command attempts to access paths outside the workspace.
 func (r *ConfineToWorkspace) Evaluate(cmd parser.Command) Decision {
+	// docker run mounts expose host paths into a container regardless of
+	// other confinement; check each host side explicitly for a precise reason.
+	for _, src := range dockerMountSources(cmd) {
+		if r.violatesBoundary(src) {
+			return Decision{
+				Allowed: false,
+				Reason:  "docker mount exposes host path outside workspace: " + src,
+			}
+		}
+	}
+
+	// A cd that lands outside the workspace re-bases every later segment,
+	// so judge the whole command by where each cd goes.
+	for _, target := range cdTargets(cmd) {
+		if r.Protector.IsProtected(target) {
+			return Decision{
+				Allowed: false,
+				Reason:  "cd into protected path: " + target,
+			}
+		}
+		if r.violatesBoundary(target) {
+			return Decision{
+				Allowed: false,
+				Reason:  "cd leaves the workspace: " + target,
+			}
+		}
+	}
+
+	// A new git worktree is a full working tree wherever its path lands,
+	// so check the destination explicitly for a worktree-specific reason.
+	for _, target := range worktreeTargets(cmd) {
+		if r.BlockWorktrees {
+			return Decision{
+				Allowed: false,
+				Reason:  "git worktree creation is blocked by workspace.block_worktrees",
+			}
+		}
+		if r.violatesBoundary(target) {
+			return Decision{
+				Allowed: false,
+				Reason:  "git worktree add would create a working tree outside the workspace: " + target,
+			}
+		}
+	}
+
 	candidates := collectPathCandidates(cmd)
+	dest := copyMoveDestination(cmd)
 
 	for _, p := range candidates {
-		if IsAlwaysProtected(p) {
+		if r.Protector.IsProtected(p) {
 			return Decision{
 				Allowed: false,
 				Reason:  "protected path: " + p + " (hardcoded security boundary)",
@@ -44,9 +101,17 @@ func (r *ConfineToWorkspace) Evaluate(cmd parser.Command) Decision {
 			}
 		}
 		if r.violatesBoundary(p) {
+			reason := "workspace boundary: " + p + " is outside project directory"
+			if dest != "" {
+				if p == dest {
+					reason += " (copy/move destination: writing outside workspace)"
+				} else {
+					reason += " (copy/move source: reading outside workspace)"
+				}
+			}
 			return Decision{
 				Allowed: false,
-				Reason:  "workspace boundary: " + p + " is outside project directory",
+				Reason:  reason,
 			}
 		}
 	}
@@ -54,6 +119,123 @@ func (r *ConfineToWorkspace) Evaluate(cmd parser.Command) Decision {
 	return Decision{Allowed: true}
 }
 
+// dockerMountSources returns the host side of each -v/--volume/--mount
+// argument of a docker run command. Other commands yield nothing.
+func dockerMountSources(cmd parser.Command) []string {
+	if cmd.Program != "docker" || cmd.Subcommand != "run" {
+		return nil
+	}
+
+	var sources []string
+	add := func(src string) {
+		if src != "" {
+			sources = append(sources, src)
+		}
+	}
+
+	fields := strings.Fields(cmd.Raw)
+	for i, f := range fields {
+		switch {
+		case f == "-v" || f == "--volume":
+			if i+1 < len(fields) {
+				add(volumeSource(fields[i+1]))
+			}
+		case strings.HasPrefix(f, "-v="):
+			add(volumeSource(strings.TrimPrefix(f, "-v=")))
+		case strings.HasPrefix(f, "--volume="):
+			add(volumeSource(strings.TrimPrefix(f, "--volume=")))
+		case f == "--mount":
+			if i+1 < len(fields) {
+				add(mountSource(fields[i+1]))
+			}
+		case strings.HasPrefix(f, "--mount="):
+			add(mountSource(strings.TrimPrefix(f, "--mount=")))
+		}
+	}
+	return sources
+}
+
+// cdTargets returns the directory arguments of cd segments in a command.
+func cdTargets(cmd parser.Command) []string {
+	var targets []string
+	for _, seg := range parser.SplitSegments(cmd.Raw) {
+		parsed := parser.Parse(strings.TrimSpace(seg))
+		if parsed.Program != "cd" || len(parsed.Args) == 0 {
+			continue
+		}
+		targets = append(targets, parsed.Args[0])
+	}
+	return targets
+}
+
+// worktreeTargets returns the path arguments of git worktree add segments.
+func worktreeTargets(cmd parser.Command) []string {
+	var targets []string
+	for _, seg := range parser.SplitSegments(cmd.Raw) {
+		parsed := parser.Parse(strings.TrimSpace(seg))
+		if parsed.Program != "git" || parsed.Subcommand != "worktree" {
+			continue
+		}
+
+		// Scan the raw tokens after add, skipping flags and the branch
+		// name consumed by -b/-B, so the path argument is found reliably.
+		fields := strings.Fields(seg)
+		for i, f := range fields {
+			if f != "add" {
+				continue
+			}
+			rest := fields[i+1:]
+			for j := 0; j < len(rest); j++ {
+				tok := rest[j]
+				if tok == "-b" || tok == "-B" {
+					j++ // skip the branch name
+					continue
+				}
+				if strings.HasPrefix(tok, "-") {
+					continue
+				}
+				targets = append(targets, tok)
+				break
+			}
+			break
+		}
+	}
+	return targets
+}
+
+// volumeSource extracts the host path from a -v host:container[:opts] spec.
+func volumeSource(spec string) string {
+	if idx := strings.Index(spec, ":"); idx > 0 {
+		return spec[:idx]
+	}
+	return ""
+}
+
+// mountSource extracts the source= (or src=) field of a --mount option list.
+func mountSource(spec string) string {
+	for _, field := range strings.Split(spec, ",") {
+		if strings.HasPrefix(field, "source=") {
+			return strings.TrimPrefix(field, "source=")
+		}
+		if strings.HasPrefix(field, "src=") {
+			return strings.TrimPrefix(field, "src=")
+		}
+	}
+	return ""
+}
+
+// copyMoveDestination returns the destination argument for known copy/move
+// commands (cp, mv), or "" when the command is not one of them.
+func copyMoveDestination(cmd parser.Command) string {
+	if cmd.Program != "cp" && cmd.Program != "mv" {
+		return ""
+	}
+	if len(cmd.Args) < 2 {
+		return ""
+	}
+	return cmd.Args[len(cmd.Args)-1]
+}
+
 // isBlocked checks if a path matches any block pattern.
 func (r *ConfineToWorkspace) isBlocked(p string) bool {
 	for _, pattern := range r.Block {
@@ -81,18 +263,18 @@ func (r *ConfineToWorkspace) violatesBoundary(p string) bool {
 		return false
 	}
 
-	cwd, err := os.Getwd()
+	cwd, err := getwd()
 	if err != nil {
-		return true // fail closed
+		// Apply the configured getwd-failure policy, consistent with the
+		// protection check.
+		return !getwdFailOpen
 	}
 
-	var absPath string
-	if filepath.IsAbs(p) {
-		absPath = filepath.Clean(p)
-	} else {
-		absPath = filepath.Clean(filepath.Join(cwd, p))
+	if r.StripRootPrefix {
+		p = stripRootPrefix(p, cwd)
 	}
 
+	absPath := absFromCwd(p, cwd)
 	cwdClean := filepath.Clean(cwd)
 	isInside := absPath == cwdClean || strings.HasPrefix(absPath, cwdClean+string(filepath.Separator))
 
@@ -106,13 +288,44 @@ func (r *ConfineToWorkspace) violatesBoundary(p string) bool {
 		return false
 	}
 
-	if r.isAllowed(p) {
+	// Test both the raw candidate and its resolved absolute form, so a
+	// relative path resolving into an allowed directory is permitted.
+	if r.isAllowed(p) || r.isAllowed(absPath) {
 		return false
 	}
 
 	return true
 }
 
+// absFromCwd resolves a candidate against the working directory, normalizing
+// ./ and ../ segments. In-workspace traversal like a/../a/x resolves back
+// inside and passes the boundary check; only genuine escapes remain outside.
+func absFromCwd(p, cwd string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Clean(filepath.Join(cwd, p))
+}
+
+// stripRootPrefix drops a leading path segment that repeats the workspace
+// directory name, normalizing proj/src/x.go to src/x.go when run from proj/.
+func stripRootPrefix(p, cwd string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+
+	root := filepath.Base(cwd)
+	if root == "" || root == "." || root == string(filepath.Separator) {
+		return p
+	}
+
+	prefix := root + string(filepath.Separator)
+	if p == root || !strings.HasPrefix(p, prefix) {
+		return p
+	}
+	return strings.TrimPrefix(p, prefix)
+}
+
 // isClaudeOperationalPath checks if the path is within Claude Code's
 // operational directories that it needs to function (plans, todos, etc.)
 func isClaudeOperationalPath(absPath string) bool {
@@ -144,22 +357,54 @@ func isClaudeOperationalPath(absPath string) bool {
 
 func collectPathCandidates(cmd parser.Command) []string {
 	var out []string
+	seen := make(map[string]bool)
 
-	out = append(out, cmd.Args...)
-
-	for _, v := range cmd.Flags {
-		if v != "" {
-			out = append(out, v)
+	add := func(v string) {
+		if v == "" || seen[v] {
+			return
 		}
+		seen[v] = true
+		out = append(out, v)
 	}
 
+	for _, v := range cmd.Args {
+		add(v)
+	}
+	for _, v := range cmd.Flags {
+		add(v)
+	}
 	for _, v := range cmd.Env {
-		out = append(out, v)
+		add(v)
 	}
 
 	return out
 }
 
+// PathCandidates exposes the workspace rule's candidate collection for
+// diagnostic commands like explain.
+func PathCandidates(cmd parser.Command) []string {
+	return collectPathCandidates(cmd)
+}
+
+// ExplainPath classifies a single candidate the way Evaluate would, for
+// diagnostic output: protected, blocked, outside (with a traversal note for
+// .. paths) or inside.
+func (r *ConfineToWorkspace) ExplainPath(p string) string {
+	switch {
+	case r.Protector.IsProtected(p):
+		return "protected"
+	case r.isBlocked(p):
+		return "blocked by workspace.block"
+	case r.violatesBoundary(p):
+		if strings.Contains(p, "..") {
+			return "outside workspace (path traversal)"
+		}
+		return "outside workspace"
+	default:
+		return "inside workspace"
+	}
+}
+
 // ViolatesWorkspaceBoundary checks if a path escapes the workspace.
 // This is the legacy function for backward compatibility.
 func ViolatesWorkspaceBoundary(p string) bool {
@@ -2,6 +2,7 @@ package policy
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/adrianpk/watchman/internal/config"
@@ -268,6 +269,31 @@ func TestEvaluateWithBlockList(t *testing.T) {
 	}
 }
 
+func TestEvaluateAllowsRelativePathInAllowedDir(t *testing.T) {
+	rule := &ConfineToWorkspace{
+		Allow: []string{"/tmp/"},
+	}
+
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+
+	// t.TempDir lives under /tmp, so ../shared.txt resolves into the
+	// allowed directory even though the raw candidate is relative.
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := parser.Command{Args: []string{"../shared.txt"}}
+	decision := rule.Evaluate(cmd)
+
+	if !decision.Allowed {
+		t.Errorf("relative path resolving into allowed dir should pass: %s", decision.Reason)
+	}
+}
+
 func TestEvaluateWithAllowList(t *testing.T) {
 	rule := &ConfineToWorkspace{
 		Allow: []string{"/tmp/"},
@@ -348,3 +374,316 @@ func TestWorkspaceAllowsClaudeOperationalPaths(t *testing.T) {
 		t.Error("should block Claude credentials path")
 	}
 }
+
+func TestWorkspaceCopyMoveSourceVsDestination(t *testing.T) {
+	rule := &ConfineToWorkspace{}
+
+	tests := []struct {
+		name       string
+		cmd        string
+		wantReason string
+	}{
+		{
+			name:       "absolute copy destination",
+			cmd:        "cp -r . /backup",
+			wantReason: "copy/move destination: writing outside workspace",
+		},
+		{
+			name:       "move with outside source",
+			cmd:        "mv ../outside.go .",
+			wantReason: "copy/move source: reading outside workspace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rule.Evaluate(parser.Parse(tt.cmd))
+			if decision.Allowed {
+				t.Fatalf("expected %q to be denied", tt.cmd)
+			}
+			if !strings.Contains(decision.Reason, tt.wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", decision.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCopyMoveDestination(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"cp a.go b.go", "b.go"},
+		{"mv src/a.go /etc/a.go", "/etc/a.go"},
+		{"cp a.go", ""},
+		{"rm a.go b.go", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			got := copyMoveDestination(parser.Parse(tt.cmd))
+			if got != tt.want {
+				t.Errorf("copyMoveDestination(%q) = %q, want %q", tt.cmd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectPathCandidatesDedup(t *testing.T) {
+	cmd := parser.Parse("cat notes.txt notes.txt notes.txt")
+	candidates := collectPathCandidates(cmd)
+
+	count := 0
+	for _, c := range candidates {
+		if c == "notes.txt" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected duplicate args to appear once, got %d in %v", count, candidates)
+	}
+}
+
+func BenchmarkCollectPathCandidates(b *testing.B) {
+	cmd := parser.Parse("cp src/a.go src/b.go src/a.go src/b.go src/a.go dst/")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		collectPathCandidates(cmd)
+	}
+}
+
+func TestStripRootPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		cwd  string
+		want string
+	}{
+		{
+			name: "duplicated root segment",
+			path: "proj/src/x.go",
+			cwd:  "/home/user/proj",
+			want: "src/x.go",
+		},
+		{
+			name: "no duplicate",
+			path: "src/x.go",
+			cwd:  "/home/user/proj",
+			want: "src/x.go",
+		},
+		{
+			name: "absolute path untouched",
+			path: "/home/user/proj/src/x.go",
+			cwd:  "/home/user/proj",
+			want: "/home/user/proj/src/x.go",
+		},
+		{
+			name: "bare root name untouched",
+			path: "proj",
+			cwd:  "/home/user/proj",
+			want: "proj",
+		},
+		{
+			name: "similar prefix not stripped",
+			path: "project/src/x.go",
+			cwd:  "/home/user/proj",
+			want: "project/src/x.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stripRootPrefix(tt.path, tt.cwd)
+			if got != tt.want {
+				t.Errorf("stripRootPrefix(%q, %q) = %q, want %q", tt.path, tt.cwd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViolatesBoundaryDotDotNormalization(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd)
+
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		violates bool
+	}{
+		{"in-workspace traversal", "a/../a/x", false},
+		{"traversal through sibling", "foo/../bar", false},
+		{"dot-slash prefix", "./src/main.go", false},
+		{"escape above root", "foo/../..", true},
+		{"plain escape", "../outside", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ViolatesWorkspaceBoundary(tt.path)
+			if got != tt.violates {
+				t.Errorf("ViolatesWorkspaceBoundary(%q) = %v, want %v", tt.path, got, tt.violates)
+			}
+		})
+	}
+}
+
+func TestDockerMountSources(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+		want []string
+	}{
+		{"short volume flag", "docker run -v /etc:/x img", []string{"/etc"}},
+		{"embedded volume flag", "docker run --volume=/var/run:/y img", []string{"/var/run"}},
+		{"mount flag", "docker run --mount type=bind,source=/etc,target=/x img", []string{"/etc"}},
+		{"mount src shorthand", "docker run --mount=type=bind,src=/home,target=/h img", []string{"/home"}},
+		{"relative mount", "docker run -v ./data:/x img", []string{"./data"}},
+		{"named volume", "docker run -v mydata:/x img", []string{"mydata"}},
+		{"not docker run", "docker ps -v", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dockerMountSources(parser.Parse(tt.cmd))
+			if len(got) != len(tt.want) {
+				t.Fatalf("dockerMountSources(%q) = %v, want %v", tt.cmd, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("source[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateDockerMounts(t *testing.T) {
+	rule := &ConfineToWorkspace{}
+
+	tests := []struct {
+		name    string
+		cmd     string
+		allowed bool
+	}{
+		{"absolute host mount", "docker run -v /etc:/x img", false},
+		{"host root mount", "docker run -v /:/host img", false},
+		{"workspace-relative mount", "docker run -v ./data:/x img", true},
+		{"named volume", "docker run -v mydata:/x img", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rule.Evaluate(parser.Parse(tt.cmd))
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Evaluate(%q) allowed = %v, want %v (%s)",
+					tt.cmd, decision.Allowed, tt.allowed, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateCdGuard(t *testing.T) {
+	rule := &ConfineToWorkspace{}
+
+	tests := []struct {
+		name    string
+		cmd     string
+		allowed bool
+	}{
+		{"cd outside then read", "cd /etc && cat passwd", false},
+		{"cd outside alone", "cd /etc", false},
+		{"cd parent escape", "cd .. && ls", false},
+		{"cd inside then build", "cd src && go build", true},
+		{"cd relative subdir chain", "cd internal/policy; go test", true},
+		{"no cd", "go build ./...", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rule.Evaluate(parser.Parse(tt.cmd))
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Evaluate(%q) allowed = %v, want %v (%s)",
+					tt.cmd, decision.Allowed, tt.allowed, decision.Reason)
+			}
+			if !tt.allowed && !strings.Contains(decision.Reason, "cd") {
+				t.Errorf("reason = %q, want cd-specific reason", decision.Reason)
+			}
+		})
+	}
+}
+
+func TestCdTargets(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+		want []string
+	}{
+		{"single cd", "cd /etc && cat passwd", []string{"/etc"}},
+		{"two cds", "cd src && make; cd /tmp", []string{"src", "/tmp"}},
+		{"bare cd skipped", "cd && ls", nil},
+		{"no cd", "go build", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cdTargets(parser.Parse(tt.cmd))
+			if len(got) != len(tt.want) {
+				t.Fatalf("cdTargets(%q) = %v, want %v", tt.cmd, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("target[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateWorktreeTargets(t *testing.T) {
+	rule := &ConfineToWorkspace{}
+
+	tests := []struct {
+		name    string
+		cmd     string
+		allowed bool
+	}{
+		{"absolute escape", "git worktree add /tmp/other", false},
+		{"relative escape", "git worktree add ../other", false},
+		{"escape with new branch", "git worktree add -b feature ../other", false},
+		{"inside workspace", "git worktree add trees/feature", true},
+		{"worktree list", "git worktree list", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := rule.Evaluate(parser.Parse(tt.cmd))
+			if decision.Allowed != tt.allowed {
+				t.Errorf("Evaluate(%q) allowed = %v, want %v (%s)",
+					tt.cmd, decision.Allowed, tt.allowed, decision.Reason)
+			}
+			if !tt.allowed && !strings.Contains(decision.Reason, "worktree") {
+				t.Errorf("reason = %q, want worktree-specific reason", decision.Reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateBlockWorktrees(t *testing.T) {
+	rule := &ConfineToWorkspace{BlockWorktrees: true}
+
+	decision := rule.Evaluate(parser.Parse("git worktree add trees/feature"))
+	if decision.Allowed {
+		t.Error("block_worktrees should deny even in-workspace worktrees")
+	}
+	if !strings.Contains(decision.Reason, "block_worktrees") {
+		t.Errorf("reason = %q, want block_worktrees mention", decision.Reason)
+	}
+}
@@ -0,0 +1,74 @@
+package policy
+
+import "github.com/adrianpk/watchman/internal/config"
+
+// defaultFilesystemTools are the built-in tools that touch the filesystem.
+var defaultFilesystemTools = map[string]bool{
+	"Bash":  true,
+	"Read":  true,
+	"Write": true,
+	"Edit":  true,
+	"Glob":  true,
+	"Grep":  true,
+}
+
+// defaultModifyingTools are the built-in tools that modify files.
+var defaultModifyingTools = map[string]bool{
+	"Write":        true,
+	"Edit":         true,
+	"NotebookEdit": true,
+}
+
+// ToolClasses is the single source of truth for tool classification. It
+// starts from the built-in sets and is augmented by tools.filesystem and
+// tools.modifying from config, so custom tools can opt into the rules that
+// gate on these classes.
+type ToolClasses struct {
+	filesystem map[string]bool
+	modifying  map[string]bool
+}
+
+// NewToolClasses creates a tool classification from config, merged with the
+// built-in defaults. A tool listed as modifying is also a filesystem tool.
+func NewToolClasses(cfg *config.ToolsConfig) *ToolClasses {
+	c := &ToolClasses{
+		filesystem: make(map[string]bool, len(defaultFilesystemTools)),
+		modifying:  make(map[string]bool, len(defaultModifyingTools)),
+	}
+	for tool := range defaultFilesystemTools {
+		c.filesystem[tool] = true
+	}
+	for tool := range defaultModifyingTools {
+		c.modifying[tool] = true
+	}
+
+	if cfg != nil {
+		for _, tool := range cfg.Filesystem {
+			c.filesystem[tool] = true
+		}
+		for _, tool := range cfg.Modifying {
+			c.modifying[tool] = true
+			c.filesystem[tool] = true
+		}
+	}
+
+	return c
+}
+
+// IsFilesystem reports whether the tool touches the filesystem.
+// A nil receiver falls back to the built-in defaults.
+func (c *ToolClasses) IsFilesystem(tool string) bool {
+	if c == nil {
+		return defaultFilesystemTools[tool]
+	}
+	return c.filesystem[tool]
+}
+
+// IsModifying reports whether the tool modifies files.
+// A nil receiver falls back to the built-in defaults.
+func (c *ToolClasses) IsModifying(tool string) bool {
+	if c == nil {
+		return defaultModifyingTools[tool]
+	}
+	return c.modifying[tool]
+}
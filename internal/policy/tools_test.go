@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestNewToolClassesDefaults(t *testing.T) {
+	classes := NewToolClasses(nil)
+
+	for _, tool := range []string{"Bash", "Read", "Write", "Edit", "Glob", "Grep"} {
+		if !classes.IsFilesystem(tool) {
+			t.Errorf("IsFilesystem(%q) = false, want true", tool)
+		}
+	}
+	for _, tool := range []string{"Write", "Edit", "NotebookEdit"} {
+		if !classes.IsModifying(tool) {
+			t.Errorf("IsModifying(%q) = false, want true", tool)
+		}
+	}
+	if classes.IsFilesystem("WebSearch") {
+		t.Error("IsFilesystem(WebSearch) = true, want false")
+	}
+	if classes.IsModifying("Read") {
+		t.Error("IsModifying(Read) = true, want false")
+	}
+}
+
+func TestNewToolClassesAugmented(t *testing.T) {
+	classes := NewToolClasses(&config.ToolsConfig{
+		Filesystem: []string{"TreeView"},
+		Modifying:  []string{"PatchApply"},
+	})
+
+	if !classes.IsFilesystem("TreeView") {
+		t.Error("IsFilesystem(TreeView) = false, want true")
+	}
+	if classes.IsModifying("TreeView") {
+		t.Error("IsModifying(TreeView) = true, want false")
+	}
+	// Modifying implies filesystem
+	if !classes.IsModifying("PatchApply") || !classes.IsFilesystem("PatchApply") {
+		t.Error("PatchApply should be both modifying and filesystem")
+	}
+	// Defaults remain intact
+	if !classes.IsModifying("Write") {
+		t.Error("IsModifying(Write) = false, want true")
+	}
+}
+
+func TestToolClassesNilReceiver(t *testing.T) {
+	var classes *ToolClasses
+	if !classes.IsFilesystem("Bash") {
+		t.Error("nil receiver should fall back to default filesystem set")
+	}
+	if !classes.IsModifying("Write") {
+		t.Error("nil receiver should fall back to default modifying set")
+	}
+}
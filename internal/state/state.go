@@ -5,18 +5,24 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/adrianpk/watchman/internal/config"
+	"github.com/adrianpk/watchman/internal/policy"
 )
 
 const stateFileName = ".watchman-state"
 
 // State represents the persistent state for reminders.
 type State struct {
-	TaskCount   int                  `json:"task_count"`
-	LastChecked map[string]time.Time `json:"last_checked"` // Per-reminder last trigger time
-	TaskCounts  map[string]int       `json:"task_counts"`  // Per-reminder task count since last trigger
+	TaskCount    int                  `json:"task_count"`
+	LastChecked  map[string]time.Time `json:"last_checked"`          // Per-reminder last trigger time
+	TaskCounts   map[string]int       `json:"task_counts"`           // Per-reminder task count since last trigger
+	SessionBytes int                  `json:"session_bytes"`         // Cumulative bytes written this session
+	ScopeAllow   []string             `json:"scope_allow,omitempty"` // Session-scoped additions to scope.allow
+	DenyCounts   map[string]int       `json:"deny_counts,omitempty"` // Per-code deny/ask tallies
 }
 
 // Manager handles state persistence and reminder checks.
@@ -71,6 +77,52 @@ func (m *Manager) IncrementTaskCount() {
 	}
 }
 
+// SessionBytes returns the cumulative bytes written this session.
+func (m *Manager) SessionBytes() int {
+	return m.state.SessionBytes
+}
+
+// AddSessionBytes adds to the cumulative written-byte counter and returns
+// the new total.
+func (m *Manager) AddSessionBytes(n int) int {
+	m.state.SessionBytes += n
+	return m.state.SessionBytes
+}
+
+// ScopeAllow returns the session-scoped additions to scope.allow.
+func (m *Manager) ScopeAllow() []string {
+	return m.state.ScopeAllow
+}
+
+// AddScopeAllow records a session-scoped scope.allow pattern.
+func (m *Manager) AddScopeAllow(pattern string) {
+	for _, p := range m.state.ScopeAllow {
+		if p == pattern {
+			return
+		}
+	}
+	m.state.ScopeAllow = append(m.state.ScopeAllow, pattern)
+}
+
+// ClearScopeAllow removes all session-scoped scope.allow patterns.
+func (m *Manager) ClearScopeAllow() {
+	m.state.ScopeAllow = nil
+}
+
+// RecordDeny increments the tally for a decision code, so `watchman stats`
+// can report which rules fire most often.
+func (m *Manager) RecordDeny(code string) {
+	if m.state.DenyCounts == nil {
+		m.state.DenyCounts = make(map[string]int)
+	}
+	m.state.DenyCounts[code]++
+}
+
+// DenyCounts returns the per-code deny/ask tallies.
+func (m *Manager) DenyCounts() map[string]int {
+	return m.state.DenyCounts
+}
+
 // CheckReminders checks all configured reminders and returns any triggered messages.
 func (m *Manager) CheckReminders(reminders []config.ReminderConfig) []string {
 	var triggered []string
@@ -100,7 +152,7 @@ func (m *Manager) CheckReminders(reminders []config.ReminderConfig) []string {
 		}
 
 		if shouldTrigger {
-			triggered = append(triggered, r.Message)
+			triggered = append(triggered, formatReminder(r.Message))
 			// Reset counters for this reminder
 			m.state.TaskCounts[r.Name] = 0
 			m.state.LastChecked[r.Name] = now
@@ -110,6 +162,25 @@ func (m *Manager) CheckReminders(reminders []config.ReminderConfig) []string {
 	return triggered
 }
 
+// modifiedCount supplies the {n} value for reminder message templates.
+// Declared as a variable so tests can inject a fixed count.
+var modifiedCount = policy.ModifiedFileCount
+
+// formatReminder interpolates template placeholders in a reminder message.
+// {n} expands to the current git modified-file count (0 when git is
+// unavailable).
+func formatReminder(message string) string {
+	if !strings.Contains(message, "{n}") {
+		return message
+	}
+
+	n := modifiedCount()
+	if n < 0 {
+		n = 0
+	}
+	return strings.ReplaceAll(message, "{n}", strconv.Itoa(n))
+}
+
 // StatePath returns the path to the state file.
 func (m *Manager) StatePath() string {
 	return m.statePath
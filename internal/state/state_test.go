@@ -0,0 +1,61 @@
+package state
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrianpk/watchman/internal/config"
+)
+
+func TestCheckRemindersModifiedCountTemplate(t *testing.T) {
+	orig := modifiedCount
+	defer func() { modifiedCount = orig }()
+	modifiedCount = func() int { return 7 }
+
+	m := &Manager{statePath: filepath.Join(t.TempDir(), ".watchman-state")}
+	if err := m.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	reminders := []config.ReminderConfig{
+		{
+			Name:       "commit-nudge",
+			Message:    "You have {n} uncommitted files - consider committing.",
+			EveryTasks: 1,
+		},
+	}
+
+	m.IncrementTaskCount()
+	m.state.TaskCounts["commit-nudge"] = 1
+
+	triggered := m.CheckReminders(reminders)
+	if len(triggered) != 1 {
+		t.Fatalf("expected 1 triggered reminder, got %d", len(triggered))
+	}
+	if !strings.Contains(triggered[0], "7 uncommitted files") {
+		t.Errorf("message = %q, want it to contain the modified-file count", triggered[0])
+	}
+}
+
+func TestFormatReminder(t *testing.T) {
+	orig := modifiedCount
+	defer func() { modifiedCount = orig }()
+
+	modifiedCount = func() int { return 3 }
+	if got := formatReminder("{n} files changed"); got != "3 files changed" {
+		t.Errorf("formatReminder() = %q, want %q", got, "3 files changed")
+	}
+
+	// No placeholder: message passes through without invoking git
+	modifiedCount = func() int { t.Error("modifiedCount should not be called"); return 0 }
+	if got := formatReminder("plain message"); got != "plain message" {
+		t.Errorf("formatReminder() = %q, want %q", got, "plain message")
+	}
+
+	// Git unavailable clamps to zero
+	modifiedCount = func() int { return -1 }
+	if got := formatReminder("{n} files"); got != "0 files" {
+		t.Errorf("formatReminder() = %q, want %q", got, "0 files")
+	}
+}